@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"glo/ipc"
+	"glo/wm"
+	"os"
+	"strings"
+)
+
+// msg is `glo msg <command>`, it sends one command to the running glo and
+// prints the reply. the exit status is 1 if glo reports an error
+func msg(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: glo msg <command> [args...]")
+		return 2
+	}
+
+	req := ipc.Request{Command: args[0], Args: args[1:]}
+	if req.Command == "subscribe" {
+		return follow(req)
+	}
+
+	reply, err := ipc.Send(req)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	out, _ := json.Marshal(reply)
+	fmt.Println(string(out))
+	if !reply.OK {
+		return 1
+	}
+	return 0
+}
+
+// follow prints the subscribe reply and then every event until glo exits
+func follow(req ipc.Request) int {
+	status := 0
+	first := true
+	err := ipc.Subscribe(req, func(line []byte) bool {
+		fmt.Println(string(line))
+		if first {
+			first = false
+			var reply ipc.Reply
+			if json.Unmarshal(line, &reply) != nil || !reply.OK {
+				status = 1
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return status
+}
+
+// query is `glo query windows|monitors|workspaces|tree|state`, it prints the
+// answer as indented JSON
+func query(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: glo query %s\n", strings.Join(wm.Queries, "|"))
+		return 2
+	}
+
+	reply, err := ipc.Send(ipc.Request{Command: "query", Args: args})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !reply.OK {
+		fmt.Fprintln(os.Stderr, reply.Error)
+		return 1
+	}
+
+	var out bytes.Buffer
+	if raw, ok := reply.Data.(json.RawMessage); ok {
+		json.Indent(&out, raw, "", "  ")
+	}
+	fmt.Println(out.String())
+	return 0
+}
@@ -0,0 +1,90 @@
+//go:build !windows
+
+package main
+
+import (
+	"glo/ipc"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// running serves control for a fresh manager on a socket of the test's own
+func running(t *testing.T) {
+	t.Helper()
+	t.Setenv("TMPDIR", t.TempDir())
+	m, _ := newManager(t)
+	srv, err := ipc.Listen(control(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve()
+	t.Cleanup(func() { srv.Close() })
+}
+
+// output runs f and returns its exit status and what it printed
+func output(t *testing.T, f func() int) (int, string) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = w, w
+	status := f()
+	os.Stdout, os.Stderr = stdout, stderr
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return status, strings.TrimSpace(string(out))
+}
+
+func TestMsg(t *testing.T) {
+	running(t)
+	for _, tc := range []struct {
+		args   []string
+		status int
+		out    string
+	}{
+		{nil, 2, "usage: glo msg"},
+		{[]string{"toggle"}, 0, `{"ok":true}`},
+		{[]string{"workspace", "3"}, 0, `{"ok":true}`},
+		{[]string{"workspace", "12"}, 1, `{"ok":false,"error":"workspace: workspace must be 1-9 (usage: workspace 1-9)"}`},
+		{[]string{"fly"}, 1, `{"ok":false,"error":"unknown command \"fly\""}`},
+		{[]string{"query", "monitors"}, 0, `{"ok":true,"data":[{"handle":1,"primary":true,"area":{"x":0,"y":0,"w":1000,"h":500},"workspace":3}]}`},
+		{[]string{"subscribe", "window_moved"}, 1, `{"ok":false,"error":"unknown event \"window_moved\"`},
+	} {
+		status, out := output(t, func() int { return msg(tc.args) })
+		if status != tc.status || !strings.HasPrefix(out, tc.out) {
+			t.Errorf("glo msg %s exited %d printing %s, want %d and %s", strings.Join(tc.args, " "), status, out, tc.status, tc.out)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	running(t)
+	for _, tc := range []struct {
+		args   []string
+		status int
+	}{
+		{nil, 2},
+		{[]string{"windows", "monitors"}, 2},
+		{[]string{"state"}, 0},
+		{[]string{"everything"}, 1},
+	} {
+		if status, _ := output(t, func() int { return query(tc.args) }); status != tc.status {
+			t.Errorf("glo query %s exited %d, want %d", strings.Join(tc.args, " "), status, tc.status)
+		}
+	}
+	status, out := output(t, func() int { return query([]string{"windows"}) })
+	if status != 0 || out != "[]" {
+		t.Errorf("glo query windows exited %d printing %s", status, out)
+	}
+}
+
+func TestMsgNotRunning(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	if status, _ := output(t, func() int { return msg([]string{"toggle"}) }); status != 1 {
+		t.Fatalf("exited %d with nothing listening, want 1", status)
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"glo/hotkey"
+	"glo/layout"
+	"glo/rules"
+	"glo/wm"
+	"os"
+	"path/filepath"
+)
+
+//go:embed default.conf
+var defaultConfig []byte
+
+// Config is everything glo reads from its config file
+type Config struct {
+	// Path is the file the config came from, empty for the built-in defaults
+	Path string
+
+	Gaps        layout.Gaps
+	SmartGaps   bool
+	MasterFrac  float64
+	NMaster     int
+	Orientation layout.Orientation
+	Layout      string
+	WideLayout  string
+	WideAspect  float64
+	SideWeights [2]float64
+	Alternate   bool
+	EvenRows    bool
+	ColumnWidth float64
+	AdoptOrder  wm.AdoptOrder
+	TileOnStart bool
+
+	Bindings []Binding
+	Rules    rules.Set
+
+	// how many of Rules came from this file rather than the one under it
+	own int
+}
+
+// Binding is a wm.Binding plus where it was declared so problems registering
+// it can be reported against the right file
+type Binding struct {
+	wm.Binding
+	File string
+	Line int
+}
+
+func (b Binding) Pos() string {
+	return fmt.Sprintf("%s:%d", b.File, b.Line)
+}
+
+// DefaultPath is %APPDATA%\glo\glo.conf on Windows and the matching user
+// config directory elsewhere
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("can't find config directory: %v", err)
+	}
+	return filepath.Join(dir, "glo", "glo.conf"), nil
+}
+
+// Default is the built-in config, the same text as default.conf
+func Default() *Config {
+	c, err := Parse("default.conf", defaultConfig, &Config{})
+	if err != nil {
+		panic(fmt.Sprintf("built-in config is invalid: %v", err))
+	}
+	c.Path = ""
+	return c
+}
+
+// Load reads path on top of the defaults. a missing file isn't an error, it
+// just means the defaults are used
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read config: %v", err)
+	}
+	return Parse(path, data, Default())
+}
+
+// Options is the part of the config the window manager takes
+func (c *Config) Options() wm.Options {
+	return wm.Options{
+		Gaps:        c.Gaps,
+		SmartGaps:   c.SmartGaps,
+		MasterFrac:  c.MasterFrac,
+		NMaster:     c.NMaster,
+		Orientation: c.Orientation,
+		Layout:      c.Layout,
+		WideLayout:  c.WideLayout,
+		WideAspect:  c.WideAspect,
+		SideWeights: c.SideWeights,
+		Alternate:   c.Alternate,
+		EvenRows:    c.EvenRows,
+		ColumnWidth: c.ColumnWidth,
+		AdoptOrder:  c.AdoptOrder,
+		Rules:       c.Rules,
+	}
+}
+
+// WMBindings strips the line numbers off Bindings, in the same order
+func (c *Config) WMBindings() []wm.Binding {
+	out := make([]wm.Binding, len(c.Bindings))
+	for i, b := range c.Bindings {
+		out[i] = b.Binding
+	}
+	return out
+}
+
+// bind adds or replaces the binding for key
+func (c *Config) bind(key hotkey.Key, command, file string, line int) {
+	b := Binding{wm.Binding{Key: key, Command: command}, file, line}
+	for i := range c.Bindings {
+		if c.Bindings[i].Key == key {
+			c.Bindings[i] = b
+			return
+		}
+	}
+	c.Bindings = append(c.Bindings, b)
+}
+
+func (c *Config) unbind(key hotkey.Key) bool {
+	for i, b := range c.Bindings {
+		if b.Key == key {
+			c.Bindings = append(c.Bindings[:i], c.Bindings[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
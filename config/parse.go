@@ -0,0 +1,304 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"glo/hotkey"
+	"glo/layout"
+	"glo/rules"
+	"glo/wm"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Parse reads config text on top of base, which is left untouched. every bad
+// line is reported as "name:line: problem" and nothing is returned unless the
+// whole file is valid, so a half-edited file never gets applied
+func Parse(name string, data []byte, base *Config) (*Config, error) {
+	c := *base
+	c.Path = name
+	c.Bindings = append([]Binding(nil), base.Bindings...)
+	// the file's own rules go in front of the ones it builds on so they are
+	// checked first
+	c.Rules = append(rules.Set(nil), base.Rules...)
+	c.own = 0
+
+	var errs []error
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		fields, err := split(sc.Text())
+		if err == nil && len(fields) > 0 {
+			err = c.directive(fields, name, n)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %v", name, n, err))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %v", name, err))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return &c, nil
+}
+
+func (c *Config) directive(fields []string, file string, line int) error {
+	name, args := fields[0], fields[1:]
+	switch name {
+	case "gap", "padding":
+		// one for every side, two for top and bottom then left and right,
+		// or four going round from the top
+		var sides []int
+		for _, a := range args {
+			n, err := strconv.Atoi(a)
+			if err != nil || n < 0 {
+				return fmt.Errorf("%s must be whole numbers of pixels, got %q", name, a)
+			}
+			sides = append(sides, n)
+		}
+		switch len(sides) {
+		case 1:
+			c.Gaps = c.Gaps.Outer(sides[0])
+		case 2:
+			c.Gaps.Top, c.Gaps.Right, c.Gaps.Bottom, c.Gaps.Left = sides[0], sides[1], sides[0], sides[1]
+		case 4:
+			c.Gaps.Top, c.Gaps.Right, c.Gaps.Bottom, c.Gaps.Left = sides[0], sides[1], sides[2], sides[3]
+		default:
+			return fmt.Errorf("%s wants 1, 2 or 4 numbers", name)
+		}
+
+	case "inner-gap":
+		if len(args) != 1 {
+			return fmt.Errorf("inner-gap wants one number")
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return fmt.Errorf("inner-gap must be a whole number of pixels, got %q", args[0])
+		}
+		c.Gaps.Inner = n
+
+	case "smart-gaps":
+		if len(args) != 1 {
+			return fmt.Errorf("smart-gaps wants yes or no")
+		}
+		on, err := parseBool(args[0])
+		if err != nil {
+			return err
+		}
+		c.SmartGaps = on
+
+	case "master":
+		if len(args) != 1 {
+			return fmt.Errorf("master wants one fraction")
+		}
+		f, err := strconv.ParseFloat(args[0], 64)
+		if err != nil || f < 0.1 || f > 0.9 {
+			return fmt.Errorf("master must be between 0.1 and 0.9, got %q", args[0])
+		}
+		c.MasterFrac = f
+
+	case "nmaster":
+		if len(args) != 1 {
+			return fmt.Errorf("nmaster wants one number")
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("nmaster must be a whole number from 1, got %q", args[0])
+		}
+		c.NMaster = n
+
+	case "orientation":
+		if len(args) != 1 {
+			return fmt.Errorf("orientation wants left, right, top or bottom")
+		}
+		o, err := layout.ParseOrientation(args[0])
+		if err != nil {
+			return err
+		}
+		c.Orientation = o
+
+	case "layout":
+		if len(args) != 1 {
+			return fmt.Errorf("layout wants one name")
+		}
+		if _, err := layout.New(args[0]); err != nil {
+			return err
+		}
+		c.Layout = args[0]
+
+	case "wide-layout":
+		if len(args) == 1 && args[0] == "off" {
+			c.WideLayout, c.WideAspect = "", 0
+			return nil
+		}
+		if len(args) != 2 {
+			return fmt.Errorf("wide-layout wants a name and an aspect ratio, or off")
+		}
+		if _, err := layout.New(args[0]); err != nil {
+			return err
+		}
+		aspect, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || aspect <= 0 {
+			return fmt.Errorf("wide-layout aspect ratio must be a positive number, got %q", args[1])
+		}
+		c.WideLayout, c.WideAspect = args[0], aspect
+
+	case "side-weights":
+		if len(args) != 2 {
+			return fmt.Errorf("side-weights wants a left and a right weight")
+		}
+		for i, a := range args {
+			w, err := strconv.ParseFloat(a, 64)
+			if err != nil || w <= 0 {
+				return fmt.Errorf("side-weights must be positive numbers, got %q", a)
+			}
+			c.SideWeights[i] = w
+		}
+
+	case "side-fill":
+		if len(args) != 1 || (args[0] != "even" && args[0] != "alternate") {
+			return fmt.Errorf("side-fill wants even or alternate")
+		}
+		c.Alternate = args[0] == "alternate"
+
+	case "grid-rows":
+		if len(args) != 1 || (args[0] != "full" && args[0] != "even") {
+			return fmt.Errorf("grid-rows wants full or even")
+		}
+		c.EvenRows = args[0] == "even"
+
+	case "column-width":
+		if len(args) != 1 {
+			return fmt.Errorf("column-width wants one fraction")
+		}
+		w, err := layout.ParseWidth(args[0])
+		if err != nil {
+			return err
+		}
+		c.ColumnWidth = w
+
+	case "adopt":
+		if len(args) != 1 {
+			return fmt.Errorf("adopt wants one order")
+		}
+		order, err := wm.ParseAdoptOrder(args[0])
+		if err != nil {
+			return err
+		}
+		c.AdoptOrder = order
+
+	case "tile-on-start":
+		if len(args) != 1 {
+			return fmt.Errorf("tile-on-start wants yes or no")
+		}
+		on, err := parseBool(args[0])
+		if err != nil {
+			return err
+		}
+		c.TileOnStart = on
+
+	case "bind":
+		if len(args) < 2 {
+			return fmt.Errorf("bind wants a key and a command")
+		}
+		key, err := hotkey.Parse(args[0])
+		if err != nil {
+			return err
+		}
+		command := strings.Join(args[1:], " ")
+		if err := wm.ValidateCommand(command); err != nil {
+			return err
+		}
+		c.bind(key, command, file, line)
+
+	case "unbind":
+		if len(args) != 1 {
+			return fmt.Errorf("unbind wants a key or all")
+		}
+		if args[0] == "all" {
+			c.Bindings = nil
+			return nil
+		}
+		key, err := hotkey.Parse(args[0])
+		if err != nil {
+			return err
+		}
+		if !c.unbind(key) {
+			return fmt.Errorf("%s isn't bound", args[0])
+		}
+
+	case "rule":
+		r, err := rules.Parse(args)
+		if err != nil {
+			return err
+		}
+		if r.Workspace > wm.WorkspaceCount {
+			return fmt.Errorf("rule workspace must be 1-%d", wm.WorkspaceCount)
+		}
+		c.Rules = slices.Insert(c.Rules, c.own, r)
+		c.own++
+
+	case "unrule":
+		if len(args) != 1 || args[0] != "all" {
+			return fmt.Errorf("unrule only takes all, to drop the default rules")
+		}
+		c.Rules = c.Rules[:c.own]
+
+	default:
+		return fmt.Errorf("unknown directive %q", name)
+	}
+	return nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "on", "true":
+		return true, nil
+	case "no", "off", "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("want yes or no, got %q", s)
+}
+
+// split breaks a line into fields on spaces. double quotes group a field
+// and # starts a comment outside of them
+func split(line string) ([]string, error) {
+	var fields []string
+	var cur strings.Builder
+	inField, quoted := false, false
+
+loop:
+	for _, r := range line {
+		switch {
+		case quoted && r == '"':
+			quoted = false
+		case quoted:
+			cur.WriteRune(r)
+		case r == '"':
+			quoted, inField = true, true
+		case r == '#':
+			break loop
+		case r == ' ' || r == '\t':
+			if inField {
+				fields = append(fields, cur.String())
+				cur.Reset()
+				inField = false
+			}
+		default:
+			cur.WriteRune(r)
+			inField = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inField {
+		fields = append(fields, cur.String())
+	}
+	return fields, nil
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"glo/hotkey"
+	"glo/layout"
+	"glo/wm"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, text string) *Config {
+	t.Helper()
+	c, err := Parse("test.conf", []byte(text), Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestDefault(t *testing.T) {
+	c, err := Parse("default.conf", defaultConfig, &Config{})
+	if err != nil {
+		t.Fatalf("the shipped default.conf doesn't load: %v", err)
+	}
+	if c.Gaps != (layout.Gaps{}.Outer(30)) || c.MasterFrac != 0.6 || c.NMaster != 1 || c.Layout != "master-stack" {
+		t.Errorf("defaults are %+v", c)
+	}
+	if c.ColumnWidth != 0.5 || c.WideLayout != "" || c.TileOnStart {
+		t.Errorf("defaults are %+v", c)
+	}
+	if len(c.Bindings) == 0 || len(c.Rules) == 0 {
+		t.Errorf("%d default bindings and %d rules", len(c.Bindings), len(c.Rules))
+	}
+	// every default binding has to register without clashing
+	seen := make(map[hotkey.Key]string)
+	for _, b := range c.Bindings {
+		if err := b.Key.Validate(); err != nil {
+			t.Errorf("%s: %v", b.Pos(), err)
+		}
+		if other, ok := seen[b.Key]; ok {
+			t.Errorf("%s: %s is bound to %q and %q", b.Pos(), b.Key, other, b.Command)
+		}
+		seen[b.Key] = b.Command
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	for _, tc := range []struct {
+		text  string
+		check func(c *Config) bool
+	}{
+		{"gap 10", func(c *Config) bool { return c.Gaps == layout.Gaps{Top: 10, Right: 10, Bottom: 10, Left: 10} }},
+		{"gap 10 20", func(c *Config) bool { return c.Gaps == layout.Gaps{Top: 10, Right: 20, Bottom: 10, Left: 20} }},
+		{"gap 1 2 3 4", func(c *Config) bool { return c.Gaps == layout.Gaps{Top: 1, Right: 2, Bottom: 3, Left: 4} }},
+		{"padding 5", func(c *Config) bool { return c.Gaps.Outer(5) == c.Gaps }},
+		{"inner-gap 8\ngap 2", func(c *Config) bool { return c.Gaps.Inner == 8 && c.Gaps.Top == 2 }},
+		{"smart-gaps on", func(c *Config) bool { return c.SmartGaps }},
+		{"master 0.7", func(c *Config) bool { return c.MasterFrac == 0.7 }},
+		{"nmaster 2", func(c *Config) bool { return c.NMaster == 2 }},
+		{"orientation top", func(c *Config) bool { return c.Orientation == layout.OrientTop }},
+		{"layout dwindle", func(c *Config) bool { return c.Layout == "dwindle" }},
+		{"wide-layout centered-master 2", func(c *Config) bool { return c.WideLayout == "centered-master" && c.WideAspect == 2 }},
+		{"wide-layout grid 2\nwide-layout off", func(c *Config) bool { return c.WideLayout == "" && c.WideAspect == 0 }},
+		{"side-weights 2 1", func(c *Config) bool { return c.SideWeights == [2]float64{2, 1} }},
+		{"side-fill alternate", func(c *Config) bool { return c.Alternate }},
+		{"grid-rows even", func(c *Config) bool { return c.EvenRows }},
+		{"column-width 2/3", func(c *Config) bool { return c.ColumnWidth == 2.0/3 }},
+		{"column-width 0.4", func(c *Config) bool { return c.ColumnWidth == 0.4 }},
+		{"adopt position", func(c *Config) bool { return c.AdoptOrder == wm.AdoptPosition }},
+		{"tile-on-start yes", func(c *Config) bool { return c.TileOnStart }},
+		{"  # a comment\n\n\tmaster 0.3 # trailing", func(c *Config) bool { return c.MasterFrac == 0.3 }},
+	} {
+		t.Run(strings.ReplaceAll(tc.text, "\n", "; "), func(t *testing.T) {
+			if c := parse(t, tc.text); !tc.check(c) {
+				t.Fatalf("got %+v", c)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want string
+	}{
+		{"gap", "test.conf:1: gap wants 1, 2 or 4 numbers"},
+		{"gap 1 2 3", "test.conf:1: gap wants 1, 2 or 4 numbers"},
+		{"gap -1", `test.conf:1: gap must be whole numbers of pixels, got "-1"`},
+		{"inner-gap x", `test.conf:1: inner-gap must be a whole number of pixels, got "x"`},
+		{"smart-gaps maybe", `test.conf:1: want yes or no, got "maybe"`},
+		{"master 0.95", `test.conf:1: master must be between 0.1 and 0.9, got "0.95"`},
+		{"nmaster 0", `test.conf:1: nmaster must be a whole number from 1, got "0"`},
+		{"orientation up", `test.conf:1: unknown orientation "up" (want left, right, top or bottom)`},
+		{"layout spiral", `test.conf:1: unknown layout "spiral"`},
+		{"wide-layout grid", "test.conf:1: wide-layout wants a name and an aspect ratio, or off"},
+		{"wide-layout grid -2", `test.conf:1: wide-layout aspect ratio must be a positive number, got "-2"`},
+		{"side-weights 1", "test.conf:1: side-weights wants a left and a right weight"},
+		{"side-fill random", "test.conf:1: side-fill wants even or alternate"},
+		{"grid-rows some", "test.conf:1: grid-rows wants full or even"},
+		{"column-width 3/2", `test.conf:1: column width must be a fraction between 0.1 and 1, got "3/2"`},
+		{"bind win+o", "test.conf:1: bind wants a key and a command"},
+		{"bind win+nokey toggle", `test.conf:1: unknown key "nokey" in "win+nokey"`},
+		{"bind win+o fly", `test.conf:1: unknown command "fly"`},
+		{"unbind win+f12", "test.conf:1: win+f12 isn't bound"},
+		{"unrule some", "test.conf:1: unrule only takes all, to drop the default rules"},
+		{"rule class=x workspace 12", "test.conf:1: rule workspace must be 1-9"},
+		{`rule title="open float`, "test.conf:1: unterminated quote"},
+		{"colour red", `test.conf:1: unknown directive "colour"`},
+		{"master 0.5\n\nmaster 2", `test.conf:3: master must be between 0.1 and 0.9, got "2"`},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			c, err := Parse("test.conf", []byte(tc.text), Default())
+			if err == nil {
+				t.Fatalf("parsed to %+v", c)
+			}
+			if c != nil {
+				t.Error("a config came back with the error")
+			}
+			if !strings.HasPrefix(err.Error(), tc.want) {
+				t.Fatalf("got %q, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestEveryBadLineReported(t *testing.T) {
+	_, err := Parse("test.conf", []byte("master 2\ngap 10\nnope\n"), Default())
+	if err == nil {
+		t.Fatal("no error")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "test.conf:1:") || !strings.HasPrefix(lines[1], "test.conf:3:") {
+		t.Fatalf("got %q", err)
+	}
+}
+
+func TestParseLeavesBaseAlone(t *testing.T) {
+	base := Default()
+	before := *base
+	before.Bindings = append([]Binding(nil), base.Bindings...)
+
+	parse(t, "master 0.3\nunbind all\nbind win+o toggle\nunrule all\nrule class=x float")
+	if _, err := Parse("test.conf", []byte("master 0.3\nbogus"), base); err == nil {
+		t.Fatal("no error")
+	}
+	if base.MasterFrac != before.MasterFrac || !reflect.DeepEqual(base.Bindings, before.Bindings) || len(base.Rules) != len(Default().Rules) {
+		t.Fatal("parsing changed the config it built on")
+	}
+}
+
+func TestBindings(t *testing.T) {
+	c := parse(t, "bind win+shift+o rotate\nunbind win+shift+period\nbind win+o workspace 3")
+	var toggle, rotate, ws3 bool
+	for _, b := range c.Bindings {
+		switch b.Key.String() {
+		case "win+shift+o":
+			// rebinding a key replaces it where it was
+			toggle = b.Command == "rotate" && b.File == "test.conf" && b.Line == 1
+		case "win+shift+period":
+			rotate = true
+		case "win+o":
+			ws3 = b.Command == "workspace 3" && b.Line == 3
+		}
+	}
+	if !toggle || rotate || !ws3 {
+		t.Fatalf("bindings are %+v", c.Bindings)
+	}
+	if n := len(c.Bindings); n != len(Default().Bindings) {
+		t.Fatalf("%d bindings, want %d", n, len(Default().Bindings))
+	}
+
+	c = parse(t, "unbind all\nbind win+o toggle")
+	if len(c.Bindings) != 1 || c.Bindings[0].Command != "toggle" {
+		t.Fatalf("bindings are %+v", c.Bindings)
+	}
+}
+
+func TestRuleOrder(t *testing.T) {
+	c := parse(t, `rule title="Picture in picture" float`+"\nrule process=steam.exe ignore")
+	defaults := Default().Rules
+	if len(c.Rules) != len(defaults)+2 {
+		t.Fatalf("%d rules", len(c.Rules))
+	}
+	// the file's own rules come first, in the order written
+	if c.Rules[0].Text != `title="Picture in picture" float` || c.Rules[1].Text != "process=steam.exe ignore" {
+		t.Fatalf("rules start %q, %q", c.Rules[0], c.Rules[1])
+	}
+	if c.Rules[2].Text != defaults[0].Text {
+		t.Fatalf("defaults start at %q", c.Rules[2])
+	}
+
+	c = parse(t, "rule class=a float\nunrule all\nrule class=b float")
+	if len(c.Rules) != 2 || c.Rules[0].Text != "class=a float" || c.Rules[1].Text != "class=b float" {
+		t.Fatalf("rules after unrule all are %v", c.Rules)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"  bind\twin+o   toggle  ", []string{"bind", "win+o", "toggle"}},
+		{`rule title="a # b" float # comment`, []string{"rule", "title=a # b", "float"}},
+		{`rule title="" float`, []string{"rule", "title=", "float"}},
+		{`a"b c"d`, []string{"ab cd"}},
+	} {
+		got, err := split(tc.line)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("split(%q) = %q, %v, want %q", tc.line, got, err, tc.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "glo.conf")
+
+	c, err := Load(path)
+	if err != nil || c.Path != "" || c.MasterFrac != 0.6 {
+		t.Fatalf("a missing file loaded as %+v, %v", c, err)
+	}
+
+	os.WriteFile(path, []byte("master 0.4\n"), 0o644)
+	c, err = Load(path)
+	if err != nil || c.Path != path || c.MasterFrac != 0.4 || c.Gaps.Top != 30 {
+		t.Fatalf("loaded %+v, %v", c, err)
+	}
+
+	// a broken file is an error, whoever loaded it keeps what they had
+	os.WriteFile(path, []byte("master 0.4\nmaster\n"), 0o644)
+	if c, err := Load(path); err == nil || c != nil || !strings.Contains(err.Error(), path+":2:") {
+		t.Fatalf("broken file loaded as %+v, %v", c, err)
+	}
+}
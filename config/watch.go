@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Watch polls path every interval and calls onChange with the reloaded config
+// (or why it couldn't be loaded) whenever the file's size or modification time
+// changes, including when it is created or deleted. it returns when stop is
+// closed
+func Watch(path string, interval time.Duration, stop <-chan struct{}, onChange func(*Config, error)) {
+	last := stamp(path)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+
+		now := stamp(path)
+		if now == last {
+			continue
+		}
+		last = now
+		onChange(Load(path))
+	}
+}
+
+type fileStamp struct {
+	exists bool
+	size   int64
+	mod    time.Time
+}
+
+func stamp(path string) fileStamp {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{true, fi.Size(), fi.ModTime()}
+}
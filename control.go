@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"glo/ipc"
+	"glo/wm"
+	"strings"
+)
+
+// control answers requests on the control pipe. everything but subscribe and
+// query is a wm command
+func control(m *wm.WindowManager) ipc.Handler {
+	return func(req ipc.Request) ipc.Reply {
+		switch req.Command {
+		case "subscribe":
+			return subscribe(m, req.Args)
+		case "query":
+			if len(req.Args) != 1 {
+				return ipc.Fail(fmt.Errorf("usage: query %s", strings.Join(wm.Queries, "|")))
+			}
+			data, err := m.Query(req.Args[0])
+			if err != nil {
+				return ipc.Fail(err)
+			}
+			return ipc.OKData(data)
+		}
+		if err := m.Exec(req.Line()); err != nil {
+			return ipc.Fail(err)
+		}
+		return ipc.OK()
+	}
+}
+
+// subscribe is "subscribe [snapshot] [event...]", no events means all of
+// them and snapshot attaches the full state to each one. the reply carries
+// the state as it is now so a bar can draw before anything happens
+func subscribe(m *wm.WindowManager, args []string) ipc.Reply {
+	snapshot := false
+	want := make(map[string]bool)
+	for _, a := range args {
+		if a == "snapshot" {
+			snapshot = true
+			continue
+		}
+		if err := wm.ValidateEventType(a); err != nil {
+			return ipc.Fail(err)
+		}
+		want[a] = true
+	}
+
+	events, cancel := m.Subscribe()
+	out := make(chan any)
+	quit := make(chan struct{})
+	go func() {
+		defer close(out)
+		for ev := range events {
+			if len(want) > 0 && !want[ev.Type] {
+				continue
+			}
+			if !snapshot {
+				ev.State = nil
+			}
+			select {
+			case out <- ev:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	reply := ipc.OKData(m.State())
+	reply.Stream = out
+	reply.Cancel = func() {
+		close(quit)
+		cancel()
+	}
+	return reply
+}
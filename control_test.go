@@ -0,0 +1,81 @@
+package main
+
+import (
+	"glo/ipc"
+	"glo/platform/fake"
+	"glo/wm"
+	"testing"
+	"time"
+)
+
+func newManager(t *testing.T) (*wm.WindowManager, *fake.Backend) {
+	t.Helper()
+	b := fake.New(1000, 500)
+	return wm.New(b, fake.NewClock(time.Unix(0, 0)), wm.Options{MasterFrac: 0.5}), b
+}
+
+// feed hands the manager whatever the backend queued
+func feed(m *wm.WindowManager, b *fake.Backend) {
+	for {
+		select {
+		case ev := <-b.Events():
+			m.HandleEvent(ev)
+		default:
+			return
+		}
+	}
+}
+
+func next(t *testing.T, stream <-chan any) wm.Event {
+	t.Helper()
+	select {
+	case v := <-stream:
+		return v.(wm.Event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+	return wm.Event{}
+}
+
+func TestSubscribeFilters(t *testing.T) {
+	m, b := newManager(t)
+	handle := control(m)
+
+	reply := handle(ipc.Request{Command: "subscribe", Args: []string{wm.EventFocusChanged, wm.EventWindowUnmanaged}})
+	if !reply.OK || reply.Stream == nil {
+		t.Fatalf("got %+v", reply)
+	}
+	if state, ok := reply.Data.(wm.State); !ok || state.Tiling {
+		t.Fatalf("the reply carries %#v, want the state", reply.Data)
+	}
+	snap := handle(ipc.Request{Command: "subscribe", Args: []string{"snapshot", wm.EventWindowManaged}})
+
+	m.Toggle()
+	a := b.Open(fake.Window{Title: "a", W: 300, H: 200})
+	feed(m, b)
+	b.Destroy(a)
+	feed(m, b)
+
+	if ev := next(t, reply.Stream); ev.Type != wm.EventFocusChanged || ev.Hwnd != a || ev.State != nil {
+		t.Fatalf("got %+v, want focus_changed for %#x without a state", ev, a)
+	}
+	if ev := next(t, reply.Stream); ev.Type != wm.EventWindowUnmanaged || ev.Hwnd != a {
+		t.Fatalf("got %+v, want window_unmanaged for %#x", ev, a)
+	}
+	if ev := next(t, snap.Stream); ev.Type != wm.EventWindowManaged || ev.State == nil || len(ev.State.Monitors[0].Occupied) != 1 {
+		t.Fatalf("got %+v, want window_managed with a snapshot", ev)
+	}
+
+	reply.Cancel()
+	snap.Cancel()
+	for range reply.Stream {
+	}
+}
+
+func TestSubscribeUnknownEvent(t *testing.T) {
+	m, _ := newManager(t)
+	reply := control(m)(ipc.Request{Command: "subscribe", Args: []string{"window_moved"}})
+	if reply.OK || reply.Stream != nil || reply.Error == "" {
+		t.Fatalf("got %+v", reply)
+	}
+}
@@ -2,16 +2,7 @@ package hotkey
 
 import (
 	"fmt"
-	"syscall"
-	"unsafe"
-)
-
-var (
-	user32           = syscall.NewLazyDLL("user32.dll")
-	registerHotKey   = user32.NewProc("RegisterHotKey")
-	unregisterHotKey = user32.NewProc("UnregisterHotKey")
-	getMessage       = user32.NewProc("GetMessageW")
-	postQuitMessage  = user32.NewProc("PostQuitMessage")
+	"strings"
 )
 
 const (
@@ -19,63 +10,121 @@ const (
 	MOD_CONTROL = 0x0002
 	MOD_SHIFT   = 0x0004
 	MOD_WIN     = 0x0008
-	WM_HOTKEY   = 0x0312
+
+	modMask = MOD_ALT | MOD_CONTROL | MOD_SHIFT | MOD_WIN
 )
 
-func RegisterGlobalHotkey(id, modifiers, vk int) error {
-	r, _, err := registerHotKey.Call(0, uintptr(id), uintptr(modifiers), uintptr(vk))
-	if r == 0 {
-		if err == syscall.Errno(0) {
-			err = syscall.GetLastError()
+// Key is one hotkey, a set of MOD_* modifiers and a virtual key code
+type Key struct {
+	Modifiers int
+	VK        int
+}
+
+// modifiers in the order String writes them
+var modifiers = []struct {
+	name string
+	mod  int
+}{
+	{"win", MOD_WIN},
+	{"ctrl", MOD_CONTROL},
+	{"alt", MOD_ALT},
+	{"shift", MOD_SHIFT},
+}
+
+var modifierAliases = map[string]int{
+	"super":   MOD_WIN,
+	"control": MOD_CONTROL,
+}
+
+// Parse reads a binding like "win+shift+o" or "alt+ctrl+F5": any modifiers,
+// each at most once, then exactly one key. names are case insensitive, the
+// OEM names from the Windows headers ("oem_period") work too and keys
+// without a name can be given by code ("vk_0xe8")
+func Parse(s string) (Key, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(s)), "+")
+	for _, p := range parts {
+		if p == "" {
+			return Key{}, fmt.Errorf("empty key name in %q", s)
 		}
-		fmt.Printf("[hotkey] failed to register hotkey (id=%d, mod=%d, vk=%d): %v\n", id, modifiers, vk, err)
-		return err
 	}
-	//fmt.Printf("[hotkey] registered hotkey id=%d mod=%d vk=%d\n", id, modifiers, vk)
 
-	return nil
+	var k Key
+	for _, p := range parts[:len(parts)-1] {
+		mod, ok := modifier(p)
+		if !ok {
+			return Key{}, fmt.Errorf("unknown modifier %q in %q", p, s)
+		}
+		if k.Modifiers&mod != 0 {
+			return Key{}, fmt.Errorf("modifier %q given twice in %q", p, s)
+		}
+		k.Modifiers |= mod
+	}
+
+	last := parts[len(parts)-1]
+	if _, ok := modifier(last); ok {
+		return Key{}, fmt.Errorf("%q has no key after its modifiers", s)
+	}
+	vk, ok := VK(last)
+	if !ok {
+		return Key{}, fmt.Errorf("unknown key %q in %q", last, s)
+	}
+	k.VK = vk
+
+	return k, nil
 }
 
-func UnregisterGlobalHotkey(id int) error {
-	r, _, err := unregisterHotKey.Call(0, uintptr(id))
-	if r == 0 {
-		if err == syscall.Errno(0) {
-			err = syscall.GetLastError()
+func modifier(name string) (int, bool) {
+	for _, m := range modifiers {
+		if m.name == name {
+			return m.mod, true
 		}
-		return err
 	}
-	return nil
+	mod, ok := modifierAliases[name]
+	return mod, ok
 }
 
-func PostQuit(exitCode int) {
-	postQuitMessage.Call(uintptr(exitCode))
+// String formats k the canonical way Parse reads back, modifiers in
+// win, ctrl, alt, shift order and the key's primary name, or its code for
+// keys without one
+func (k Key) String() string {
+	var b strings.Builder
+	for _, m := range modifiers {
+		if k.Modifiers&m.mod != 0 {
+			b.WriteString(m.name)
+			b.WriteByte('+')
+		}
+	}
+	if name, ok := Name(k.VK); ok {
+		b.WriteString(name)
+	} else {
+		fmt.Fprintf(&b, "vk_%#02x", k.VK)
+	}
+	return b.String()
 }
 
-func ListenHotkeys(callback func(id int)) {
-	var msg struct {
-		hwnd    uintptr
-		message uint32
-		wParam  uintptr
-		lParam  uintptr
-		time    uint32
-		pt      struct{ x, y int32 }
+// Validate reports whether k could have come from Parse
+func (k Key) Validate() error {
+	if k.Modifiers&^modMask != 0 {
+		return fmt.Errorf("unknown modifier bits %#x", k.Modifiers&^modMask)
 	}
-	for {
-		r, _, err := getMessage.Call(uintptr(unsafe.Pointer(&msg)), 0, 0, 0)
-		if r == 0 {
-			//fmt.Println("[hotkey] message loop exiting (WM_QUIT)")
-			return
-		}
-		if r == ^uintptr(0) { // -1
-			if err == syscall.Errno(0) {
-				err = syscall.GetLastError()
-			}
+	if !valid(k.VK) {
+		return fmt.Errorf("virtual key %#02x out of range", k.VK)
+	}
+	return nil
+}
 
-			fmt.Printf("[hotkey] GetMessageW error: %v\n", err)
-			continue
-		}
-		if msg.message == WM_HOTKEY {
-			callback(int(msg.wParam))
-		}
+func (k Key) MarshalText() ([]byte, error) {
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(k.String()), nil
+}
+
+func (k *Key) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
 	}
+	*k = parsed
+	return nil
 }
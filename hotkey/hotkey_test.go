@@ -0,0 +1,110 @@
+package hotkey
+
+import "testing"
+
+func TestRoundTrip(t *testing.T) {
+	for vk := 0x01; vk <= 0xFE; vk++ {
+		for _, mods := range []int{0, MOD_WIN, MOD_CONTROL | MOD_SHIFT, modMask} {
+			k := Key{Modifiers: mods, VK: vk}
+			got, err := Parse(k.String())
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", k.String(), err)
+			}
+			if got != k {
+				t.Fatalf("Parse(%q) = %+v, want %+v", k.String(), got, k)
+			}
+		}
+	}
+}
+
+func TestNamesRoundTrip(t *testing.T) {
+	for vk, name := range vkNames {
+		if got, ok := VK(name); !ok || got != vk {
+			t.Errorf("VK(%q) = %#02x, %v, want %#02x", name, got, ok, vk)
+		}
+	}
+	for name, vk := range vkAliases {
+		if _, ok := vkNames[vk]; !ok {
+			t.Errorf("alias %q is for %#02x which has no name", name, vk)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Key
+	}{
+		{"win+shift+o", Key{MOD_WIN | MOD_SHIFT, 'O'}},
+		{"alt+ctrl+F5", Key{MOD_ALT | MOD_CONTROL, 0x74}},
+		{" Super+Control+oem_period ", Key{MOD_WIN | MOD_CONTROL, 0xBE}},
+		{"win+.", Key{MOD_WIN, 0xBE}},
+		{"win+num7", Key{MOD_WIN, 0x67}},
+		{"pgdn", Key{0, 0x22}},
+		{"win+VK_0xE8", Key{MOD_WIN, 0xE8}},
+		{"vk_0x7", Key{0, 0x07}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"win+",
+		"win++o",
+		"win+shift",
+		"hyper+o",
+		"win+win+o",
+		"win+super+o",
+		"win+nosuchkey",
+		"win+o+p",
+		"vk_0x0",
+		"vk_0xff",
+		"vk_0x100",
+		"vk_e8",
+	} {
+		if k, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %+v, want an error", in, k)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		k    Key
+		want string
+	}{
+		{Key{MOD_SHIFT | MOD_ALT | MOD_CONTROL | MOD_WIN, 'A'}, "win+ctrl+alt+shift+a"},
+		{Key{MOD_WIN, 0xBB}, "win+equal"},
+		{Key{0, 0x21}, "pageup"},
+		{Key{MOD_ALT, 0xE8}, "alt+vk_0xe8"},
+	}
+	for _, tt := range tests {
+		if got := tt.k.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.k, got, tt.want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	k := Key{MOD_WIN | MOD_SHIFT, 0x70}
+	text, err := k.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Key
+	if err := back.UnmarshalText(text); err != nil || back != k {
+		t.Fatalf("UnmarshalText(%q) = %+v, %v", text, back, err)
+	}
+
+	for _, bad := range []Key{{Modifiers: 0x10, VK: 'A'}, {VK: 0}, {VK: 0x1FF}} {
+		if _, err := bad.MarshalText(); err == nil {
+			t.Errorf("MarshalText(%+v) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package hotkey
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// vkNames holds the primary name of every virtual key glo can bind, which is
+// what String writes. letters, digits, numpad digits and f1-f24 are filled in
+// by init
+var vkNames = map[int]string{
+	0x08: "backspace",
+	0x09: "tab",
+	0x0C: "clear",
+	0x0D: "enter",
+	0x13: "pause",
+	0x14: "capslock",
+	0x1B: "escape",
+	0x20: "space",
+	0x21: "pageup",
+	0x22: "pagedown",
+	0x23: "end",
+	0x24: "home",
+	0x25: "left",
+	0x26: "up",
+	0x27: "right",
+	0x28: "down",
+	0x29: "select",
+	0x2A: "print",
+	0x2B: "execute",
+	0x2C: "printscreen",
+	0x2D: "insert",
+	0x2E: "delete",
+	0x2F: "help",
+	0x5D: "apps",
+	0x5F: "sleep",
+
+	0x6A: "multiply",
+	0x6B: "add",
+	0x6C: "separator",
+	0x6D: "subtract",
+	0x6E: "decimal",
+	0x6F: "divide",
+
+	0x90: "numlock",
+	0x91: "scrolllock",
+
+	0xA6: "browser_back",
+	0xA7: "browser_forward",
+	0xA8: "browser_refresh",
+	0xA9: "browser_stop",
+	0xAA: "browser_search",
+	0xAB: "browser_favorites",
+	0xAC: "browser_home",
+	0xAD: "volume_mute",
+	0xAE: "volume_down",
+	0xAF: "volume_up",
+	0xB0: "media_next",
+	0xB1: "media_prev",
+	0xB2: "media_stop",
+	0xB3: "media_play_pause",
+	0xB4: "launch_mail",
+	0xB5: "launch_media_select",
+	0xB6: "launch_app1",
+	0xB7: "launch_app2",
+
+	0xBA: "semicolon",
+	0xBB: "equal",
+	0xBC: "comma",
+	0xBD: "minus",
+	0xBE: "period",
+	0xBF: "slash",
+	0xC0: "grave",
+	0xDB: "bracketleft",
+	0xDC: "backslash",
+	0xDD: "bracketright",
+	0xDE: "quote",
+	0xDF: "oem_8",
+	0xE2: "oem_102",
+}
+
+// vkAliases are other accepted spellings, mostly the VK_* names from the
+// Windows headers and the characters the US layout prints
+var vkAliases = map[string]int{
+	"bksp":       0x08,
+	"return":     0x0D,
+	"caps":       0x14,
+	"esc":        0x1B,
+	"prior":      0x21,
+	"pgup":       0x21,
+	"next":       0x22,
+	"pgdn":       0x22,
+	"snapshot":   0x2C,
+	"prtsc":      0x2C,
+	"ins":        0x2D,
+	"del":        0x2E,
+	"menu":       0x5D,
+	"scroll":     0x91,
+	"oem_1":      0xBA,
+	"oem_plus":   0xBB,
+	"plus":       0xBB,
+	"oem_comma":  0xBC,
+	"oem_minus":  0xBD,
+	"oem_period": 0xBE,
+	"oem_2":      0xBF,
+	"oem_3":      0xC0,
+	"backtick":   0xC0,
+	"oem_4":      0xDB,
+	"oem_5":      0xDC,
+	"oem_6":      0xDD,
+	"oem_7":      0xDE,
+	";":          0xBA,
+	"=":          0xBB,
+	",":          0xBC,
+	"-":          0xBD,
+	".":          0xBE,
+	"/":          0xBF,
+	"`":          0xC0,
+	"[":          0xDB,
+	"\\":         0xDC,
+	"]":          0xDD,
+	"'":          0xDE,
+}
+
+var nameVKs = make(map[string]int)
+
+func init() {
+	for c := 'a'; c <= 'z'; c++ {
+		vkNames[int(c-'a'+'A')] = string(c)
+	}
+	for c := '0'; c <= '9'; c++ {
+		vkNames[int(c)] = string(c)
+		vkNames[0x60+int(c-'0')] = fmt.Sprintf("num%c", c)
+		vkAliases[fmt.Sprintf("numpad%c", c)] = 0x60 + int(c-'0')
+	}
+	for n := 1; n <= 24; n++ {
+		vkNames[0x70+n-1] = fmt.Sprintf("f%d", n)
+	}
+
+	for vk, name := range vkNames {
+		nameVKs[name] = vk
+	}
+	for name, vk := range vkAliases {
+		nameVKs[name] = vk
+	}
+}
+
+// VK looks up a key by its name or an alias, names are lower case. keys
+// without a name can be given by code, as vk_ and the code in hex like
+// vk_0xe8
+func VK(name string) (int, bool) {
+	if code, ok := strings.CutPrefix(name, "vk_0x"); ok {
+		vk, err := strconv.ParseUint(code, 16, 8)
+		if err != nil || !valid(int(vk)) {
+			return 0, false
+		}
+		return int(vk), true
+	}
+	vk, ok := nameVKs[name]
+	return vk, ok
+}
+
+// valid is whether vk is in the range of virtual key codes
+func valid(vk int) bool {
+	return vk >= 0x01 && vk <= 0xFE
+}
+
+// Name is the primary name of a virtual key
+func Name(vk int) (string, bool) {
+	name, ok := vkNames[vk]
+	return name, ok
+}
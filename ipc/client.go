@@ -0,0 +1,68 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+)
+
+// Send runs one request against the glo that's running and returns its
+// reply, Data comes back as a json.RawMessage
+func Send(req Request) (Reply, error) {
+	conn, err := dial()
+	if err != nil {
+		return Reply{}, err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Reply{}, fmt.Errorf("can't send request: %v", err)
+	}
+
+	// keep data as it was sent so field order survives the round trip
+	var data json.RawMessage
+	reply := Reply{Data: &data}
+	sc := bufio.NewScanner(conn)
+	sc.Buffer(nil, 1<<20)
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return Reply{}, fmt.Errorf("can't read reply: %v", err)
+		}
+		return Reply{}, fmt.Errorf("glo closed the connection without replying")
+	}
+	if err := json.Unmarshal(sc.Bytes(), &reply); err != nil {
+		return Reply{}, fmt.Errorf("bad reply: %v", err)
+	}
+	if len(data) == 0 {
+		reply.Data = nil
+	} else {
+		reply.Data = data
+	}
+	return reply, nil
+}
+
+// Subscribe sends req and calls f with the reply and then every line glo
+// streams after it, until f returns false or glo hangs up
+func Subscribe(req Request, f func(line []byte) bool) error {
+	conn, err := dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return fmt.Errorf("can't send request: %v", err)
+	}
+
+	sc := bufio.NewScanner(conn)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		if !f(sc.Bytes()) {
+			return nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("can't read from glo: %v", err)
+	}
+	return nil
+}
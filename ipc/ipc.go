@@ -0,0 +1,49 @@
+package ipc
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrClosed is returned by Accept once the listener is closed
+var ErrClosed = errors.New("ipc: server closed")
+
+// Request is one line a client sends, e.g. {"command":"workspace","args":["3"]}.
+// the arguments can also be written into command itself
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// Line is the request as a wm command line
+func (r Request) Line() string {
+	return strings.TrimSpace(strings.Join(append([]string{r.Command}, r.Args...), " "))
+}
+
+// Reply answers a request on its own line
+type Reply struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+	Data  any    `json:"data,omitempty"`
+
+	// Stream turns the connection into a feed: after the reply every value
+	// received from it is written as its own line until it is closed or the
+	// client goes away, then Cancel is called
+	Stream <-chan any `json:"-"`
+	Cancel func()     `json:"-"`
+}
+
+func OK() Reply {
+	return Reply{OK: true}
+}
+
+func OKData(data any) Reply {
+	return Reply{OK: true, Data: data}
+}
+
+func Fail(err error) Reply {
+	return Reply{Error: err.Error()}
+}
+
+// Handler runs a request, it may be called from several goroutines at once
+type Handler func(Request) Reply
@@ -0,0 +1,227 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve starts a server for h on a socket in a temp dir of its own
+func serve(t *testing.T, h Handler) *Server {
+	t.Helper()
+	t.Setenv("TMPDIR", t.TempDir())
+	s, err := Listen(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Serve()
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func echo(req Request) Reply {
+	name, _, _ := strings.Cut(req.Line(), " ")
+	switch name {
+	case "fail":
+		return Fail(errors.New("it failed"))
+	case "data":
+		return OKData(map[string]any{"line": req.Line()})
+	}
+	return OK()
+}
+
+func TestLine(t *testing.T) {
+	for _, tc := range []struct {
+		req  Request
+		want string
+	}{
+		{Request{Command: "workspace", Args: []string{"3"}}, "workspace 3"},
+		{Request{Command: "workspace 3"}, "workspace 3"},
+		{Request{Command: " toggle "}, "toggle"},
+	} {
+		if got := tc.req.Line(); got != tc.want {
+			t.Errorf("%+v is %q, want %q", tc.req, got, tc.want)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	serve(t, echo)
+
+	reply, err := Send(Request{Command: "data", Args: []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, ok := reply.Data.(json.RawMessage); !reply.OK || !ok || string(raw) != `{"line":"data a b"}` {
+		t.Fatalf("got %+v", reply)
+	}
+
+	reply, err = Send(Request{Command: "fail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.OK || reply.Error != "it failed" || reply.Data != nil {
+		t.Fatalf("got %+v", reply)
+	}
+}
+
+func TestFraming(t *testing.T) {
+	serve(t, echo)
+	conn, err := dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// requests can come in one write, blank lines are skipped and a line
+	// that isn't JSON gets an error without dropping the connection
+	fmt.Fprint(conn, "{\"command\":\"data\",\"args\":[\"1\"]}\n\n{nope\n{\"command\":\"fail\"}\n{\"command\":\"data 2\"}\n")
+	want := []string{
+		`{"ok":true,"data":{"line":"data 1"}}`,
+		`{"ok":false,"error":"bad request: invalid character 'n' looking for beginning of object key string"}`,
+		`{"ok":false,"error":"it failed"}`,
+		`{"ok":true,"data":{"line":"data 2"}}`,
+	}
+	sc := bufio.NewScanner(conn)
+	for _, w := range want {
+		if !sc.Scan() {
+			t.Fatalf("connection ended before %s: %v", w, sc.Err())
+		}
+		if got := sc.Text(); got != w {
+			t.Fatalf("got %s, want %s", got, w)
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	stream := make(chan any)
+	cancelled := make(chan struct{})
+	serve(t, func(req Request) Reply {
+		reply := OKData("hello")
+		reply.Stream = stream
+		reply.Cancel = func() { close(cancelled) }
+		return reply
+	})
+
+	go func() {
+		for i := 1; ; i++ {
+			select {
+			case stream <- i:
+			case <-cancelled:
+				return
+			}
+		}
+	}()
+
+	var lines []string
+	err := Subscribe(Request{Command: "subscribe"}, func(line []byte) bool {
+		lines = append(lines, string(line))
+		return len(lines) < 3
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(lines, " "); got != `{"ok":true,"data":"hello"} 1 2` {
+		t.Fatalf("got %s", got)
+	}
+
+	// the client went away mid-stream, the next write fails and the server
+	// lets go of the subscription
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream wasn't cancelled after the client left")
+	}
+}
+
+func TestStreamEnds(t *testing.T) {
+	stream := make(chan any, 2)
+	stream <- "last"
+	close(stream)
+	cancelled := make(chan struct{})
+	serve(t, func(req Request) Reply {
+		reply := OK()
+		reply.Stream = stream
+		reply.Cancel = func() { close(cancelled) }
+		return reply
+	})
+
+	var lines []string
+	err := Subscribe(Request{Command: "subscribe"}, func(line []byte) bool {
+		lines = append(lines, string(line))
+		return true
+	})
+	if err != nil || len(lines) != 2 || lines[1] != `"last"` {
+		t.Fatalf("got %q, %v", lines, err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Cancel wasn't called when the stream closed")
+	}
+}
+
+func TestDisconnectBeforeReply(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	serve(t, func(req Request) Reply {
+		if req.Command != "slow" {
+			return echo(req)
+		}
+		close(started)
+		<-release
+		return OK()
+	})
+
+	conn, err := net.Dial("unix", Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintln(conn, `{"command":"slow"}`)
+	<-started
+	conn.Close()
+	close(release)
+
+	// the server is still answering other clients
+	if reply, err := Send(Request{Command: "fail"}); err != nil || reply.Error != "it failed" {
+		t.Fatalf("got %+v, %v", reply, err)
+	}
+}
+
+func TestSecondListen(t *testing.T) {
+	serve(t, echo)
+	if s, err := Listen(echo); err == nil {
+		s.Close()
+		t.Fatal("a second server listened on the same address")
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	s, err := Listen(echo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- s.Serve() }()
+
+	s.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve didn't return after Close")
+	}
+	if _, err := Send(Request{Command: "toggle"}); err == nil {
+		t.Fatal("sent to a closed server")
+	}
+}
@@ -0,0 +1,247 @@
+//go:build windows
+
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	kernel32                = syscall.NewLazyDLL("kernel32.dll")
+	procCreateNamedPipeW    = kernel32.NewProc("CreateNamedPipeW")
+	procConnectNamedPipe    = kernel32.NewProc("ConnectNamedPipe")
+	procDisconnectNamedPipe = kernel32.NewProc("DisconnectNamedPipe")
+	procProcessIdToSession  = kernel32.NewProc("ProcessIdToSessionId")
+
+	advapi32                = syscall.NewLazyDLL("advapi32.dll")
+	procConvertStringSDToSD = advapi32.NewProc("ConvertStringSecurityDescriptorToSecurityDescriptorW")
+)
+
+const (
+	PIPE_ACCESS_DUPLEX            = 0x00000003
+	FILE_FLAG_FIRST_PIPE_INSTANCE = 0x00080000
+	PIPE_TYPE_BYTE                = 0x00000000
+	PIPE_READMODE_BYTE            = 0x00000000
+	PIPE_WAIT                     = 0x00000000
+	PIPE_REJECT_REMOTE_CLIENTS    = 0x00000008
+	PIPE_UNLIMITED_INSTANCES      = 255
+
+	ERROR_FILE_NOT_FOUND = syscall.Errno(2)
+	ERROR_ACCESS_DENIED  = syscall.Errno(5)
+	ERROR_PIPE_BUSY      = syscall.Errno(231)
+	ERROR_PIPE_CONNECTED = syscall.Errno(535)
+	INVALID_HANDLE_VALUE = ^uintptr(0)
+	SDDL_REVISION_1      = 1
+	pipeBuffer           = 4096
+	dialTimeout          = 2 * time.Second
+)
+
+// Address is the named pipe glo listens on, one per user and session so
+// every desktop logged in gets its own glo
+func Address() string {
+	if p, err := owner(); err == nil {
+		return fmt.Sprintf(`\\.\pipe\glo-%s-%d`, p.sid, p.session)
+	}
+	return `\\.\pipe\glo`
+}
+
+type pipeOwner struct {
+	sid     string
+	session uint32
+}
+
+// owner is who glo runs as, the pipe is named after them and only they may
+// open it
+var owner = sync.OnceValues(func() (pipeOwner, error) {
+	token, err := syscall.OpenCurrentProcessToken()
+	if err != nil {
+		return pipeOwner{}, fmt.Errorf("can't open the process token: %v", err)
+	}
+	defer token.Close()
+	user, err := token.GetTokenUser()
+	if err != nil {
+		return pipeOwner{}, fmt.Errorf("can't read the process user: %v", err)
+	}
+	sid, err := user.User.Sid.String()
+	if err != nil {
+		return pipeOwner{}, fmt.Errorf("can't read the process user: %v", err)
+	}
+
+	var session uint32
+	r, _, e := procProcessIdToSession.Call(uintptr(syscall.Getpid()), uintptr(unsafe.Pointer(&session)))
+	if r == 0 {
+		return pipeOwner{}, fmt.Errorf("can't read the session: %v", e)
+	}
+	return pipeOwner{sid, session}, nil
+})
+
+// security gives the pipe a DACL that lets nobody but its owner in, the
+// default one lets everyone read it. the descriptor is allocated by Windows
+// and has to be freed with LocalFree
+func security() (*syscall.SecurityAttributes, error) {
+	p, err := owner()
+	if err != nil {
+		return nil, err
+	}
+	sddl, err := syscall.UTF16PtrFromString("D:P(A;;GA;;;" + p.sid + ")")
+	if err != nil {
+		return nil, err
+	}
+	sa := &syscall.SecurityAttributes{Length: uint32(unsafe.Sizeof(syscall.SecurityAttributes{}))}
+	r, _, e := procConvertStringSDToSD.Call(
+		uintptr(unsafe.Pointer(sddl)),
+		SDDL_REVISION_1,
+		uintptr(unsafe.Pointer(&sa.SecurityDescriptor)),
+		0,
+	)
+	if r == 0 {
+		return nil, fmt.Errorf("can't build the pipe's security descriptor: %v", e)
+	}
+	return sa, nil
+}
+
+// pipeListener hands out one pipe instance per client. instances are
+// blocking, ConnectNamedPipe waits for the next client and Close unblocks it
+// by connecting itself
+type pipeListener struct {
+	mu     sync.Mutex
+	next   uintptr
+	closed bool
+}
+
+func listen() (listener, error) {
+	// the first instance is made up front so a second glo fails right away
+	h, err := createPipe(true)
+	if err == ERROR_ACCESS_DENIED {
+		return nil, fmt.Errorf("another glo is already listening on %s", Address())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't create %s: %v", Address(), err)
+	}
+	return &pipeListener{next: h}, nil
+}
+
+func createPipe(first bool) (uintptr, error) {
+	name, err := syscall.UTF16PtrFromString(Address())
+	if err != nil {
+		return 0, err
+	}
+	sa, err := security()
+	if err != nil {
+		return 0, err
+	}
+	defer syscall.LocalFree(syscall.Handle(sa.SecurityDescriptor))
+
+	mode := uintptr(PIPE_ACCESS_DUPLEX)
+	if first {
+		mode |= FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+	h, _, e := procCreateNamedPipeW.Call(
+		uintptr(unsafe.Pointer(name)),
+		mode,
+		PIPE_TYPE_BYTE|PIPE_READMODE_BYTE|PIPE_WAIT|PIPE_REJECT_REMOTE_CLIENTS,
+		PIPE_UNLIMITED_INSTANCES,
+		pipeBuffer,
+		pipeBuffer,
+		0,
+		uintptr(unsafe.Pointer(sa)),
+	)
+	if h == INVALID_HANDLE_VALUE {
+		return 0, e
+	}
+	return h, nil
+}
+
+func (l *pipeListener) Accept() (io.ReadWriteCloser, error) {
+	l.mu.Lock()
+	h, closed := l.next, l.closed
+	l.next = 0
+	l.mu.Unlock()
+	if closed {
+		return nil, ErrClosed
+	}
+
+	if h == 0 {
+		var err error
+		if h, err = createPipe(false); err != nil {
+			return nil, fmt.Errorf("can't create %s: %v", Address(), err)
+		}
+	}
+
+	r, _, e := procConnectNamedPipe.Call(h, 0)
+	if r == 0 && e != ERROR_PIPE_CONNECTED {
+		syscall.CloseHandle(syscall.Handle(h))
+		return nil, fmt.Errorf("can't accept on %s: %v", Address(), e)
+	}
+
+	l.mu.Lock()
+	closed = l.closed
+	l.mu.Unlock()
+	if closed {
+		syscall.CloseHandle(syscall.Handle(h))
+		return nil, ErrClosed
+	}
+	return &pipeConn{os.NewFile(h, Address())}, nil
+}
+
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	// wake up a pending ConnectNamedPipe
+	if f, err := os.OpenFile(Address(), os.O_RDWR, 0); err == nil {
+		f.Close()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.next != 0 {
+		syscall.CloseHandle(syscall.Handle(l.next))
+		l.next = 0
+	}
+	return nil
+}
+
+// pipeConn flushes before disconnecting so the client gets the last reply
+type pipeConn struct {
+	*os.File
+}
+
+func (c *pipeConn) Close() error {
+	h := c.Fd()
+	syscall.FlushFileBuffers(syscall.Handle(h))
+	procDisconnectNamedPipe.Call(h)
+	return c.File.Close()
+}
+
+func dial() (io.ReadWriteCloser, error) {
+	deadline := time.Now().Add(dialTimeout)
+	for {
+		f, err := os.OpenFile(Address(), os.O_RDWR, 0)
+		if err == nil {
+			return f, nil
+		}
+		switch {
+		case errors.Is(err, ERROR_FILE_NOT_FOUND):
+			return nil, fmt.Errorf("can't reach glo, is it running? (%v)", err)
+		case errors.Is(err, ERROR_PIPE_BUSY) && time.Now().Before(deadline):
+			// every instance is taken, the next one is made as soon as
+			// the server gets back to Accept
+			time.Sleep(20 * time.Millisecond)
+		default:
+			return nil, fmt.Errorf("can't reach glo: %v", err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+type listener interface {
+	Accept() (io.ReadWriteCloser, error)
+	Close() error
+}
+
+// Server answers newline-delimited JSON requests on the control pipe (or
+// socket), one reply line per request line
+type Server struct {
+	l listener
+	h Handler
+
+	mu     sync.Mutex
+	conns  map[io.ReadWriteCloser]bool
+	closed bool
+	done   chan struct{}
+	// requests being handled, Close lets them finish so a client that
+	// asked glo to quit still gets its reply
+	busy sync.WaitGroup
+}
+
+// Listen claims the control address, it fails if another glo already has it
+func Listen(h Handler) (*Server, error) {
+	l, err := listen()
+	if err != nil {
+		return nil, err
+	}
+	return &Server{l: l, h: h, conns: make(map[io.ReadWriteCloser]bool), done: make(chan struct{})}, nil
+}
+
+// Serve accepts clients until Close
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.l.Accept()
+		if errors.Is(err, ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn io.ReadWriteCloser) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	enc := json.NewEncoder(conn)
+	sc := bufio.NewScanner(conn)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			return
+		}
+		s.busy.Add(1)
+		s.mu.Unlock()
+
+		reply := s.handle(sc.Bytes())
+		err := enc.Encode(reply)
+		s.busy.Done()
+		if err != nil {
+			if reply.Cancel != nil {
+				reply.Cancel()
+			}
+			return
+		}
+		if reply.Stream != nil {
+			s.stream(enc, reply)
+			return
+		}
+	}
+}
+
+// stream feeds reply.Stream to the client. nothing more is read from a
+// subscribed connection, a failed write is how a client leaving shows up
+func (s *Server) stream(enc *json.Encoder, reply Reply) {
+	defer reply.Cancel()
+	for {
+		select {
+		case <-s.done:
+			return
+		case v, ok := <-reply.Stream:
+			if !ok {
+				return
+			}
+			if err := enc.Encode(v); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) handle(line []byte) Reply {
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return Fail(fmt.Errorf("bad request: %v", err))
+	}
+	return s.h(req)
+}
+
+// Close stops accepting, waits for requests already running and drops every
+// client
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	s.mu.Unlock()
+
+	err := s.l.Close()
+	s.busy.Wait()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		// a pipe close can block behind a read that's still waiting
+		go conn.Close()
+	}
+	s.mu.Unlock()
+	return err
+}
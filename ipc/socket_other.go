@@ -0,0 +1,57 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// Address is the unix socket glo listens on, one per user
+func Address() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("glo-%d.sock", os.Getuid()))
+}
+
+type socketListener struct {
+	l net.Listener
+}
+
+func listen() (listener, error) {
+	addr := Address()
+	if c, err := net.Dial("unix", addr); err == nil {
+		c.Close()
+		return nil, fmt.Errorf("another glo is already listening on %s", addr)
+	}
+	// nobody is answering so whatever is there is left over from a crash
+	os.Remove(addr)
+
+	l, err := net.Listen("unix", addr)
+	if err != nil {
+		return nil, fmt.Errorf("can't listen on %s: %v", addr, err)
+	}
+	return socketListener{l}, nil
+}
+
+func (s socketListener) Accept() (io.ReadWriteCloser, error) {
+	c, err := s.l.Accept()
+	if errors.Is(err, net.ErrClosed) {
+		return nil, ErrClosed
+	}
+	return c, err
+}
+
+func (s socketListener) Close() error {
+	return s.l.Close()
+}
+
+func dial() (io.ReadWriteCloser, error) {
+	c, err := net.Dial("unix", Address())
+	if err != nil {
+		return nil, fmt.Errorf("can't reach glo, is it running? (%v)", err)
+	}
+	return c, nil
+}
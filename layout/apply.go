@@ -0,0 +1,33 @@
+package layout
+
+// Target is anything a plan can be pushed onto, *window.Window satisfies it
+type Target interface {
+	Hwnd() uintptr
+	Restore() error
+	SetRect(x, y, width, height int) error
+}
+
+func Items[T Target](windows []T) []Item {
+	items := make([]Item, 0, len(windows))
+	for _, w := range windows {
+		items = append(items, Item{ID: w.Hwnd()})
+	}
+	return items
+}
+
+// Apply pushes a plan onto real windows, windows not in the plan are left alone
+func Apply[T Target](plan Plan, windows []T) {
+	byID := make(map[uintptr]T, len(windows))
+	for _, w := range windows {
+		byID[w.Hwnd()] = w
+	}
+
+	for _, p := range plan {
+		w, ok := byID[p.ID]
+		if !ok {
+			continue
+		}
+		w.Restore()
+		w.SetRect(p.Rect.X, p.Rect.Y, p.Rect.W, p.Rect.H)
+	}
+}
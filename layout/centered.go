@@ -0,0 +1,70 @@
+package layout
+
+// CenteredMaster puts the master column in the middle with a stack column
+// on each side. with only one window left for the stack it falls back to
+// master-stack so nothing is squeezed for an empty column
+type CenteredMaster struct{}
+
+func (CenteredMaster) Name() string { return "centered-master" }
+
+func (CenteredMaster) Arrange(items []Item, area Rect, p Params) Plan {
+	return p.Orientation.orient(area, func(area Rect) Plan {
+		if len(items)-max(p.NMaster, 1) < 2 {
+			return masterStack(items, area, p)
+		}
+		return centered(items, area, p)
+	})
+}
+
+// ThreeColumn always splits the area into the same three columns, master in
+// the middle, even when the sides have nothing to show
+type ThreeColumn struct{}
+
+func (ThreeColumn) Name() string { return "three-column" }
+
+func (ThreeColumn) Arrange(items []Item, area Rect, p Params) Plan {
+	return p.Orientation.orient(area, func(area Rect) Plan {
+		return centered(items, area, p)
+	})
+}
+
+// centered lays out master, left and right columns, the master one
+// MasterFrac wide and the sides sharing the rest by SideWeights and the
+// average weight of their windows
+func centered(items []Item, area Rect, p Params) Plan {
+	if len(items) == 0 || area.empty() {
+		return nil
+	}
+
+	nmaster := min(max(p.NMaster, 1), len(items))
+	masterW := int(float64(area.W) * clampFrac(p.MasterFrac))
+	left, right := sides(items[nmaster:], p.Alternate)
+
+	wl, wr := weight(p.SideWeights[0])*average(left), weight(p.SideWeights[1])*average(right)
+	leftW := int(float64(area.W-masterW) * wl / (wl + wr))
+	rightW := area.W - masterW - leftW
+
+	x, y := area.X, area.Y
+	plan := make(Plan, 0, len(items))
+	plan = column(plan, items[:nmaster], Rect{x + leftW, y, masterW, area.H})
+	plan = column(plan, left, Rect{x, y, leftW, area.H})
+	plan = column(plan, right, Rect{x + leftW + masterW, y, rightW, area.H})
+	return plan
+}
+
+// sides deals the stack out to the two side columns, the left one taking
+// the first half, or one each starting on the right when alternating
+func sides(stack []Item, alternate bool) (left, right []Item) {
+	if !alternate {
+		half := (len(stack) + 1) / 2
+		return stack[:half], stack[half:]
+	}
+	for i, it := range stack {
+		if i%2 == 0 {
+			right = append(right, it)
+		} else {
+			left = append(left, it)
+		}
+	}
+	return left, right
+}
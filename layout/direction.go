@@ -0,0 +1,120 @@
+package layout
+
+import "fmt"
+
+type Direction int
+
+const (
+	Left Direction = iota
+	Right
+	Up
+	Down
+)
+
+var directionNames = []string{"left", "right", "up", "down"}
+
+func ParseDirection(s string) (Direction, error) {
+	for i, name := range directionNames {
+		if s == name {
+			return Direction(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown direction %q (want left, right, up or down)", s)
+}
+
+func (d Direction) String() string {
+	if d < 0 || int(d) >= len(directionNames) {
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+	return directionNames[d]
+}
+
+// Neighbour finds the placement next to id in direction d. only placements
+// entirely on that side count, ones lined up with id beat ones that are
+// off to the side, then the nearest wins and ties go to the one most
+// centered on id
+func Neighbour(plan Plan, id uintptr, d Direction) (uintptr, bool) {
+	var from Rect
+	found := false
+	for _, p := range plan {
+		if p.ID == id {
+			from, found = p.Rect, true
+			break
+		}
+	}
+	if !found {
+		return 0, false
+	}
+
+	var best uintptr
+	var bestScore [3]int
+	for _, p := range plan {
+		if p.ID == id {
+			continue
+		}
+		gap, side, off, ok := relate(from, p.Rect, d)
+		if !ok {
+			continue
+		}
+		score := [3]int{side, gap, off}
+		if best == 0 || less(score, bestScore) {
+			best, bestScore = p.ID, score
+		}
+	}
+	return best, best != 0
+}
+
+// relate measures to as seen from from looking d: gap is the distance
+// between the facing edges, side how far to sits past from's edges on the
+// other axis (0 if they overlap) and off how far apart their centers are on
+// that axis
+func relate(from, to Rect, d Direction) (gap, side, off int, ok bool) {
+	switch d {
+	case Left:
+		gap = from.X - (to.X + to.W)
+	case Right:
+		gap = to.X - (from.X + from.W)
+	case Up:
+		gap = from.Y - (to.Y + to.H)
+	case Down:
+		gap = to.Y - (from.Y + from.H)
+	}
+	if gap < 0 {
+		return 0, 0, 0, false
+	}
+
+	if d == Left || d == Right {
+		side = rangeGap(from.Y, from.H, to.Y, to.H)
+		off = abs((from.Y + from.H/2) - (to.Y + to.H/2))
+	} else {
+		side = rangeGap(from.X, from.W, to.X, to.W)
+		off = abs((from.X + from.W/2) - (to.X + to.W/2))
+	}
+	return gap, side, off, true
+}
+
+func rangeGap(a, alen, b, blen int) int {
+	switch {
+	case b >= a+alen:
+		return b - (a + alen)
+	case a >= b+blen:
+		return a - (b + blen)
+	}
+	return 0
+}
+
+func less(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
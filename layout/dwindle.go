@@ -0,0 +1,126 @@
+package layout
+
+// Dwindle gives each window a slice of what the previous one left: the
+// remaining space is split along its longer side, the window takes the
+// first part and the rest carry on in the second. the first split follows
+// the master fraction, the others default to halves and can be adjusted
+// per window with AdjustSplit. orientations turn and mirror it like
+// master-stack, but every split still goes along the longer side
+type Dwindle struct {
+	// split ratio of the window that takes the first part of a split
+	ratios map[uintptr]float64
+}
+
+func NewDwindle() *Dwindle {
+	return &Dwindle{ratios: make(map[uintptr]float64)}
+}
+
+func (*Dwindle) Name() string { return "dwindle" }
+
+func (d *Dwindle) Arrange(items []Item, area Rect, p Params) Plan {
+	return p.Orientation.orient(area, func(area Rect) Plan {
+		return d.arrange(items, area, p)
+	})
+}
+
+func (d *Dwindle) arrange(items []Item, area Rect, p Params) Plan {
+	if len(items) == 0 {
+		return nil
+	}
+
+	if area.empty() {
+		return nil
+	}
+	r := area
+
+	plan := make(Plan, 0, len(items))
+	for i, it := range items {
+		if i == len(items)-1 {
+			plan = append(plan, Placement{ID: it.ID, Rect: r})
+			break
+		}
+
+		first, rest := splitLonger(r, d.ratio(items, i, p))
+		plan = append(plan, Placement{ID: it.ID, Rect: first})
+		r = rest
+	}
+	return plan
+}
+
+// ratio is the share of its split items[i] takes, without one set it's
+// weighed against the window after it. the master fraction is the first
+// split at even weights
+func (d *Dwindle) ratio(items []Item, i int, p Params) float64 {
+	if i == 0 {
+		f := clampFrac(p.MasterFrac)
+		w0, w1 := f*weight(items[0].Weight), (1-f)*weight(items[1].Weight)
+		return clampFrac(w0 / (w0 + w1))
+	}
+	if r, ok := d.ratios[items[i].ID]; ok {
+		return r
+	}
+	w := weight(items[i].Weight)
+	return clampFrac(w / (w + weight(items[i+1].Weight)))
+}
+
+// AdjustSplit grows the window id by delta of the split it owns, the last
+// window owns no split so its parent's shrinks instead. the first split is
+// the master fraction, for that one it hands the caller the delta to change
+// it by, turned around if id is the second of two windows
+func (d *Dwindle) AdjustSplit(items []Item, id uintptr, delta float64) (float64, bool) {
+	i := -1
+	for j, it := range items {
+		if it.ID == id {
+			i = j
+			break
+		}
+	}
+	if i < 0 || len(items) < 2 {
+		return delta, false
+	}
+	if i == len(items)-1 {
+		i, delta = i-1, -delta
+	}
+	if i == 0 {
+		return delta, false
+	}
+
+	owner := items[i].ID
+	d.ratios[owner] = clampFrac(d.ratio(items, i, Params{}) + delta)
+
+	// forget windows that are gone
+	for id := range d.ratios {
+		if !contains(items, id) {
+			delete(d.ratios, id)
+		}
+	}
+	return 0, true
+}
+
+// Equalize drops every adjusted split, the master fraction stays
+func (d *Dwindle) Equalize() bool {
+	if len(d.ratios) == 0 {
+		return false
+	}
+	clear(d.ratios)
+	return true
+}
+
+// splitLonger cuts r across its longer side, first gets frac of it
+func splitLonger(r Rect, frac float64) (first, rest Rect) {
+	if r.W >= r.H {
+		w := int(float64(r.W) * frac)
+		return Rect{r.X, r.Y, w, r.H}, Rect{r.X + w, r.Y, r.W - w, r.H}
+	}
+	h := int(float64(r.H) * frac)
+	return Rect{r.X, r.Y, r.W, h}, Rect{r.X, r.Y + h, r.W, r.H - h}
+}
+
+func contains(items []Item, id uintptr) bool {
+	for _, it := range items {
+		if it.ID == id {
+			return true
+		}
+	}
+	return false
+}
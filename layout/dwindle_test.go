@@ -0,0 +1,101 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDwindleGolden(t *testing.T) {
+	area := Rect{0, 0, 1000, 600}
+	for _, tc := range []struct {
+		name string
+		n    int
+		o    Orientation
+		want Plan
+	}{
+		{"one", 1, OrientLeft, Plan{{1, Rect{0, 0, 1000, 600}}}},
+		{"two", 2, OrientLeft, Plan{{1, Rect{0, 0, 500, 600}}, {2, Rect{500, 0, 500, 600}}}},
+		{"three", 3, OrientLeft, Plan{
+			{1, Rect{0, 0, 500, 600}},
+			{2, Rect{500, 0, 500, 300}},
+			{3, Rect{500, 300, 500, 300}},
+		}},
+		{"four", 4, OrientLeft, Plan{
+			{1, Rect{0, 0, 500, 600}},
+			{2, Rect{500, 0, 500, 300}},
+			{3, Rect{500, 300, 250, 300}},
+			{4, Rect{750, 300, 250, 300}},
+		}},
+		{"right", 3, OrientRight, Plan{
+			{1, Rect{500, 0, 500, 600}},
+			{2, Rect{0, 0, 500, 300}},
+			{3, Rect{0, 300, 500, 300}},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := make([]uintptr, tc.n)
+			for i := range ids {
+				ids[i] = uintptr(i + 1)
+			}
+			got := NewDwindle().Arrange(items(ids...), area, Params{MasterFrac: 0.5, Orientation: tc.o})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDwindleAdjustSplit(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		n      int
+		id     uintptr
+		master float64
+		ok     bool
+	}{
+		{"master", 2, 1, 0.05, false},
+		// the second of two shares the master split, growing it shrinks master
+		{"second of two", 2, 2, -0.05, false},
+		{"own split", 3, 2, 0, true},
+		{"last takes its parent's", 3, 3, 0, true},
+		{"unknown", 3, 9, 0.05, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := make([]uintptr, tc.n)
+			for i := range ids {
+				ids[i] = uintptr(i + 1)
+			}
+			master, ok := NewDwindle().AdjustSplit(items(ids...), tc.id, 0.05)
+			if master != tc.master || ok != tc.ok {
+				t.Fatalf("got %v %v, want %v %v", master, ok, tc.master, tc.ok)
+			}
+		})
+	}
+
+	d := NewDwindle()
+	all := items(1, 2, 3)
+	d.AdjustSplit(all, 3, 0.1)
+	got := d.Arrange(all, Rect{0, 0, 1000, 600}, Params{MasterFrac: 0.5})
+	want := Plan{
+		{1, Rect{0, 0, 500, 600}},
+		{2, Rect{500, 0, 500, 240}},
+		{3, Rect{500, 240, 500, 360}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("after growing the last window got %v, want %v", got, want)
+	}
+}
+
+func TestDwindleWeights(t *testing.T) {
+	area := Rect{0, 0, 1000, 600}
+	all := []Item{{ID: 1}, {ID: 2, Weight: 1.5}, {ID: 3, Weight: 0.5}}
+	got := NewDwindle().Arrange(all, area, Params{MasterFrac: 0.5})
+	want := Plan{
+		{1, Rect{0, 0, 400, 600}},
+		{2, Rect{400, 0, 450, 600}},
+		{3, Rect{850, 0, 150, 600}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package layout
+
+// Gaps are the empty space kept between the area's edges and the windows,
+// per side, and between neighbouring windows
+type Gaps struct {
+	Top    int `json:"top"`
+	Right  int `json:"right"`
+	Bottom int `json:"bottom"`
+	Left   int `json:"left"`
+	Inner  int `json:"inner"`
+}
+
+// Outer sets every outer side to n
+func (g Gaps) Outer(n int) Gaps {
+	g.Top, g.Right, g.Bottom, g.Left = n, n, n, n
+	return g
+}
+
+// Arrange lays items out with l in area less the outer gaps, then pulls
+// neighbouring windows apart by the inner gap. edges on the outside of the
+// layout stay put so the outer gaps are exact. with Params.SmartGaps a
+// window on its own gets no gaps at all
+func Arrange(l Layout, items []Item, area Rect, p Params) Plan {
+	r, g := p.gaps(area, len(items))
+	if r.empty() {
+		return nil
+	}
+
+	plan := l.Arrange(items, r, p)
+	if g.Inner > 0 {
+		for i := range plan {
+			plan[i].Rect = gutter(plan[i].Rect, r, g.Inner)
+		}
+	}
+	return plan
+}
+
+// View is the part of area Arrange hands the layout for n windows
+func (p Params) View(area Rect, n int) Rect {
+	r, _ := p.gaps(area, n)
+	return r
+}
+
+// gaps are the gaps that apply to n windows and what they leave of area
+func (p Params) gaps(area Rect, n int) (Rect, Gaps) {
+	g := p.Gaps
+	if p.SmartGaps && n == 1 {
+		g = Gaps{}
+	}
+	return Rect{area.X + g.Left, area.Y + g.Top, area.W - g.Left - g.Right, area.H - g.Top - g.Bottom}, g
+}
+
+// gutter takes half of gap off every edge of r that isn't on the edge of
+// area, so two windows that touched end up gap apart
+func gutter(r, area Rect, gap int) Rect {
+	left, top, right, bottom := r.X, r.Y, r.X+r.W, r.Y+r.H
+	if left != area.X {
+		left += gap / 2
+	}
+	if top != area.Y {
+		top += gap / 2
+	}
+	if right != area.X+area.W {
+		right -= gap - gap/2
+	}
+	if bottom != area.Y+area.H {
+		bottom -= gap - gap/2
+	}
+	return Rect{left, top, max(right-left, 1), max(bottom-top, 1)}
+}
@@ -0,0 +1,92 @@
+package layout
+
+import "math"
+
+// Grid puts the windows in a near-square grid, as many columns as rows or
+// one more. the last row stretches across when it comes up short, or with
+// Params.EvenRows the windows are spread so rows differ by at most one.
+// windows share their row by weight and rows are as tall as their average
+type Grid struct{}
+
+func (Grid) Name() string { return "grid" }
+
+func (Grid) Arrange(items []Item, area Rect, p Params) Plan {
+	if len(items) == 0 || area.empty() {
+		return nil
+	}
+
+	n := len(items)
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := (n + cols - 1) / cols
+
+	grid := make([][]Item, rows)
+	rest := items
+	for i := range grid {
+		count := min(cols, len(rest))
+		if p.EvenRows {
+			count = n / rows
+			if i < n%rows {
+				count++
+			}
+		}
+		grid[i], rest = rest[:count], rest[count:]
+	}
+
+	// rows are as tall as the average weight of what's in them
+	heights := make([]float64, rows)
+	for i, row := range grid {
+		heights[i] = average(row)
+	}
+
+	plan := make(Plan, 0, n)
+	y := area.Y
+	for i, h := range weighted(area.H, heights) {
+		x := area.X
+		for j, w := range weighted(area.W, weights(grid[i])) {
+			plan = append(plan, Placement{ID: grid[i][j].ID, Rect: Rect{x, y, w, h}})
+			x += w
+		}
+		y += h
+	}
+	return plan
+}
+
+// Columns splits the area into columns, one per window, as wide as their
+// weights say
+type Columns struct{}
+
+func (Columns) Name() string { return "columns" }
+
+func (Columns) Arrange(items []Item, area Rect, p Params) Plan {
+	if len(items) == 0 || area.empty() {
+		return nil
+	}
+
+	plan := make(Plan, 0, len(items))
+	x := area.X
+	for i, w := range weighted(area.W, weights(items)) {
+		plan = append(plan, Placement{ID: items[i].ID, Rect: Rect{x, area.Y, w, area.H}})
+		x += w
+	}
+	return plan
+}
+
+// Rows splits the area into rows, one per window, as high as their weights
+// say
+type Rows struct{}
+
+func (Rows) Name() string { return "rows" }
+
+func (Rows) Arrange(items []Item, area Rect, p Params) Plan {
+	if len(items) == 0 || area.empty() {
+		return nil
+	}
+
+	plan := make(Plan, 0, len(items))
+	y := area.Y
+	for i, h := range weighted(area.H, weights(items)) {
+		plan = append(plan, Placement{ID: items[i].ID, Rect: Rect{area.X, y, area.W, h}})
+		y += h
+	}
+	return plan
+}
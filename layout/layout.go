@@ -1,99 +1,146 @@
 package layout
 
-import (
-	"glo/window"
-)
+type Rect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
 
-func TileWindows(windows []*window.Window, screenWidth, screenHeight, padding int, masterFrac float64) {
-	if len(windows) == 0 {
-		return
-	}
+func (r Rect) empty() bool {
+	return r.W <= 0 || r.H <= 0
+}
+
+// Item is a single window handed to a layout, ids are opaque to the layout
+type Item struct {
+	ID uintptr
+	// Weight is the item's share of a region it splits with others, relative
+	// to theirs, 0 counts as 1
+	Weight float64
+}
 
-	if masterFrac < 0.1 {
-		masterFrac = 0.1
-	} else if masterFrac > 0.9 {
-		masterFrac = 0.9
+// weight is an Item or Node weight with 0 counting as 1
+func weight(w float64) float64 {
+	if w <= 0 {
+		return 1
 	}
+	return w
+}
 
-	innerW := screenWidth - padding*2
-	innerH := screenHeight - padding*2
+func weights(items []Item) []float64 {
+	out := make([]float64, len(items))
+	for i, it := range items {
+		out[i] = weight(it.Weight)
+	}
+	return out
+}
 
-	masterW := int(float64(innerW) * masterFrac)
-	if len(windows) == 1 {
-		masterW = innerW
+// weighted cuts total into lengths in proportion to weights that add up to
+// it, leftover pixels go to the first ones
+func weighted(total int, weights []float64) []int {
+	var sum float64
+	for _, w := range weights {
+		sum += w
 	}
+	out := make([]int, len(weights))
+	left := total
+	for i, w := range weights {
+		out[i] = int(float64(total) * w / sum)
+		left -= out[i]
+	}
+	for i := 0; left > 0; i = (i + 1) % len(out) {
+		out[i]++
+		left--
+	}
+	return out
+}
 
-	master := windows[0]
-	master.Restore()
-	master.SetRect(
-		padding,
-		padding,
-		masterW,
-		innerH,
-	)
-
-	if len(windows) == 1 {
-		return
+// average is the mean weight of items, 1 if there are none
+func average(items []Item) float64 {
+	if len(items) == 0 {
+		return 1
+	}
+	var sum float64
+	for _, it := range items {
+		sum += weight(it.Weight)
 	}
+	return sum / float64(len(items))
+}
 
-	stackX := padding + masterW
-	stackW := innerW - masterW
+type Params struct {
+	// Gaps are only applied by Arrange, layouts fill the area they get
+	Gaps Gaps
+	// SmartGaps drops the gaps when there's only one window
+	SmartGaps  bool
+	MasterFrac float64
+	// NMaster is how many windows share the master column, less than 1
+	// counts as 1
+	NMaster int
+	// Orientation is the side the master goes on
+	Orientation Orientation
+	// SideWeights are the relative widths of the left and right columns in
+	// the centered layouts, 0 counts as 1
+	SideWeights [2]float64
+	// Alternate deals the stack to the right and left columns in turn
+	// rather than filling the left one first
+	Alternate bool
+	// EvenRows spreads the grid layout's windows evenly over its rows
+	// instead of filling every row but the last
+	EvenRows bool
+	// ColumnWidth is how wide new columns of the scrolling layout are, as a
+	// fraction of the area
+	ColumnWidth float64
+	// Focused is the workspace's focused window, 0 if there isn't one
+	Focused uintptr
+	// Skipped are the workspace's windows left out of items for now, like
+	// minimized and floating ones. layouts that remember where windows go
+	// keep their place
+	Skipped []uintptr
+}
 
-	stackCount := len(windows) - 1
-	eachH := innerH / stackCount
+// Equalizer is a layout that keeps sizes of its own, Equalize forgets them
+type Equalizer interface {
+	Layout
+	Equalize() bool
+}
 
-	for i, w := range windows[1:] {
-		y := padding + i*eachH
-		h := eachH
-		if i == stackCount-1 {
-			h = innerH - eachH*(stackCount-1)
-		}
-		w.Restore()
-		w.SetRect(stackX, y, stackW, h)
-	}
+type Placement struct {
+	ID   uintptr
+	Rect Rect
 }
 
-func TileWindowsInRect(windows []*window.Window, x, y, width, height, padding int, masterFrac float64) {
-	if len(windows) == 0 {
-		return
-	}
-	if masterFrac < 0.1 {
-		masterFrac = 0.1
-	} else if masterFrac > 0.9 {
-		masterFrac = 0.9
-	}
-	innerW := width - padding*2
-	innerH := height - padding*2
-	if innerW <= 0 || innerH <= 0 {
-		return
-	}
-	masterW := int(float64(innerW) * masterFrac)
-	if len(windows) == 1 {
-		masterW = innerW
-	}
+type Plan []Placement
 
-	master := windows[0]
-	master.Restore()
-	master.SetRect(
-		x+padding,
-		y+padding,
-		masterW,
-		innerH,
-	)
-	if len(windows) == 1 {
-		return
-	}
-	stackX := x + padding + masterW
-	stackW := innerW - masterW
-	stackCount := len(windows) - 1
-	eachH := innerH / stackCount
-	for i, w := range windows[1:] {
-		yy := y + padding + i*eachH
-		h := eachH
-		if i == stackCount-1 {
-			h = innerH - eachH*(stackCount-1)
-		}
-		w.Restore()
-		w.SetRect(stackX, yy, stackW, h)
+// Layout computes where windows should go without touching them
+type Layout interface {
+	Name() string
+	Arrange(items []Item, area Rect, p Params) Plan
+}
+
+// Splitter is a layout with a split ratio per window. AdjustSplit grows id
+// by delta and reports whether it had a split of its own to change, when it
+// didn't master is how much the master fraction should change instead.
+// items are the same ones Arrange gets
+type Splitter interface {
+	Layout
+	AdjustSplit(items []Item, id uintptr, delta float64) (master float64, ok bool)
+}
+
+func TileWindows[T Target](windows []T, screenWidth, screenHeight, padding int, masterFrac float64) {
+	TileWindowsInRect(windows, 0, 0, screenWidth, screenHeight, padding, masterFrac)
+}
+
+func TileWindowsInRect[T Target](windows []T, x, y, width, height, padding int, masterFrac float64) {
+	p := Params{Gaps: Gaps{}.Outer(padding), MasterFrac: masterFrac, NMaster: 1}
+	plan := Arrange(MasterStack{}, Items(windows), Rect{x, y, width, height}, p)
+	Apply(plan, windows)
+}
+
+func clampFrac(f float64) float64 {
+	if f < 0.1 {
+		return 0.1
+	} else if f > 0.9 {
+		return 0.9
 	}
+	return f
 }
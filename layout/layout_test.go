@@ -0,0 +1,191 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGolden(t *testing.T) {
+	area := Rect{0, 0, 1000, 600}
+	p := Params{MasterFrac: 0.6, NMaster: 1}
+	for _, tc := range []struct {
+		name  string
+		l     Layout
+		items []Item
+		p     func(*Params)
+		want  Plan
+	}{
+		{"master-stack one", MasterStack{}, items(1), nil, Plan{{1, Rect{0, 0, 1000, 600}}}},
+		{"master-stack", MasterStack{}, items(1, 2, 3), nil, Plan{
+			{1, Rect{0, 0, 600, 600}},
+			{2, Rect{600, 0, 400, 300}},
+			{3, Rect{600, 300, 400, 300}},
+		}},
+		{"master-stack two masters", MasterStack{}, items(1, 2, 3), func(p *Params) { p.NMaster = 2 }, Plan{
+			{1, Rect{0, 0, 600, 300}},
+			{2, Rect{0, 300, 600, 300}},
+			{3, Rect{600, 0, 400, 600}},
+		}},
+		{"master-stack weights", MasterStack{}, []Item{{ID: 1}, {ID: 2, Weight: 3}, {ID: 3}}, nil, Plan{
+			{1, Rect{0, 0, 600, 600}},
+			{2, Rect{600, 0, 400, 450}},
+			{3, Rect{600, 450, 400, 150}},
+		}},
+		// the pixels that don't divide go to the top of the stack
+		{"master-stack remainder", MasterStack{}, items(1, 2, 3, 4, 5, 6, 7, 8), nil, Plan{
+			{1, Rect{0, 0, 600, 600}},
+			{2, Rect{600, 0, 400, 86}},
+			{3, Rect{600, 86, 400, 86}},
+			{4, Rect{600, 172, 400, 86}},
+			{5, Rect{600, 258, 400, 86}},
+			{6, Rect{600, 344, 400, 86}},
+			{7, Rect{600, 430, 400, 85}},
+			{8, Rect{600, 515, 400, 85}},
+		}},
+		{"monocle", Monocle{}, items(1, 2), nil, Plan{
+			{1, Rect{0, 0, 1000, 600}},
+			{2, Rect{0, 0, 1000, 600}},
+		}},
+		{"centered-master", CenteredMaster{}, items(1, 2, 3), nil, Plan{
+			{1, Rect{200, 0, 600, 600}},
+			{2, Rect{0, 0, 200, 600}},
+			{3, Rect{800, 0, 200, 600}},
+		}},
+		{"centered-master falls back", CenteredMaster{}, items(1, 2), nil, Plan{
+			{1, Rect{0, 0, 600, 600}},
+			{2, Rect{600, 0, 400, 600}},
+		}},
+		{"centered-master side weights", CenteredMaster{}, items(1, 2, 3), func(p *Params) { p.SideWeights = [2]float64{3, 1} }, Plan{
+			{1, Rect{300, 0, 600, 600}},
+			{2, Rect{0, 0, 300, 600}},
+			{3, Rect{900, 0, 100, 600}},
+		}},
+		{"three-column keeps its columns", ThreeColumn{}, items(1, 2), nil, Plan{
+			{1, Rect{200, 0, 600, 600}},
+			{2, Rect{0, 0, 200, 600}},
+		}},
+		{"three-column alternate", ThreeColumn{}, items(1, 2, 3, 4, 5), func(p *Params) { p.Alternate = true }, Plan{
+			{1, Rect{200, 0, 600, 600}},
+			{3, Rect{0, 0, 200, 300}},
+			{5, Rect{0, 300, 200, 300}},
+			{2, Rect{800, 0, 200, 300}},
+			{4, Rect{800, 300, 200, 300}},
+		}},
+		{"grid", Grid{}, items(1, 2, 3, 4, 5), nil, Plan{
+			{1, Rect{0, 0, 334, 300}},
+			{2, Rect{334, 0, 333, 300}},
+			{3, Rect{667, 0, 333, 300}},
+			{4, Rect{0, 300, 500, 300}},
+			{5, Rect{500, 300, 500, 300}},
+		}},
+		{"grid even rows", Grid{}, items(1, 2, 3, 4, 5, 6, 7), func(p *Params) { p.EvenRows = true }, Plan{
+			{1, Rect{0, 0, 334, 200}},
+			{2, Rect{334, 0, 333, 200}},
+			{3, Rect{667, 0, 333, 200}},
+			{4, Rect{0, 200, 500, 200}},
+			{5, Rect{500, 200, 500, 200}},
+			{6, Rect{0, 400, 500, 200}},
+			{7, Rect{500, 400, 500, 200}},
+		}},
+		{"columns", Columns{}, []Item{{ID: 1}, {ID: 2, Weight: 2}, {ID: 3}}, nil, Plan{
+			{1, Rect{0, 0, 250, 600}},
+			{2, Rect{250, 0, 500, 600}},
+			{3, Rect{750, 0, 250, 600}},
+		}},
+		{"rows", Rows{}, items(1, 2, 3, 4), nil, Plan{
+			{1, Rect{0, 0, 1000, 150}},
+			{2, Rect{0, 150, 1000, 150}},
+			{3, Rect{0, 300, 1000, 150}},
+			{4, Rect{0, 450, 1000, 150}},
+		}},
+		{"no windows", MasterStack{}, nil, nil, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := p
+			if tc.p != nil {
+				tc.p(&p)
+			}
+			if got := tc.l.Arrange(tc.items, area, p); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGaps(t *testing.T) {
+	area := Rect{0, 0, 1000, 600}
+	gaps := Gaps{Top: 10, Right: 20, Bottom: 30, Left: 40}
+	for _, tc := range []struct {
+		name  string
+		inner int
+		smart bool
+		n     int
+		want  Plan
+	}{
+		{"outer", 0, false, 2, Plan{{1, Rect{40, 10, 470, 560}}, {2, Rect{510, 10, 470, 560}}}},
+		{"inner", 10, false, 2, Plan{{1, Rect{40, 10, 465, 560}}, {2, Rect{515, 10, 465, 560}}}},
+		// the odd pixel of an odd gap comes off the window before it
+		{"odd inner", 5, false, 2, Plan{{1, Rect{40, 10, 467, 560}}, {2, Rect{512, 10, 468, 560}}}},
+		{"one window", 10, false, 1, Plan{{1, Rect{40, 10, 940, 560}}}},
+		{"smart", 10, true, 1, Plan{{1, Rect{0, 0, 1000, 600}}}},
+		{"smart with two", 10, true, 2, Plan{{1, Rect{40, 10, 465, 560}}, {2, Rect{515, 10, 465, 560}}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gaps
+			g.Inner = tc.inner
+			ids := []uintptr{1, 2}[:tc.n]
+			p := Params{Gaps: g, SmartGaps: tc.smart, MasterFrac: 0.5}
+			if got := Arrange(MasterStack{}, items(ids...), area, p); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if got := Arrange(MasterStack{}, items(1), area, Params{Gaps: Gaps{}.Outer(300)}); got != nil {
+		t.Fatalf("gaps wider than the area got %v", got)
+	}
+}
+
+type target struct {
+	hwnd uintptr
+	rect Rect
+}
+
+func (t *target) Hwnd() uintptr  { return t.hwnd }
+func (t *target) Restore() error { return nil }
+func (t *target) SetRect(x, y, width, height int) error {
+	t.rect = Rect{x, y, width, height}
+	return nil
+}
+
+func TestTileWindowsInRect(t *testing.T) {
+	a, b := &target{hwnd: 1}, &target{hwnd: 2}
+	TileWindowsInRect([]*target{a, b}, 100, 50, 800, 400, 10, 0.5)
+	if want := (Rect{110, 60, 390, 380}); a.rect != want {
+		t.Errorf("master at %v, want %v", a.rect, want)
+	}
+	if want := (Rect{500, 60, 390, 380}); b.rect != want {
+		t.Errorf("stack at %v, want %v", b.rect, want)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	for _, name := range Names() {
+		l, err := New(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.Name() != name {
+			t.Errorf("New(%q) is named %q", name, l.Name())
+		}
+	}
+	// layouts with state must not share it
+	a, _ := New("scrolling")
+	b, _ := New("scrolling")
+	if a == b {
+		t.Error("New handed out the same scrolling layout twice")
+	}
+	if _, err := New("spiral"); err == nil {
+		t.Error("New accepted an unknown layout")
+	}
+}
@@ -0,0 +1,51 @@
+package layout
+
+// MasterStack puts the first NMaster windows in a master column on the left,
+// one above the other, and stacks the rest evenly on the right. other
+// orientations turn the whole thing so the master is on that side
+type MasterStack struct{}
+
+func (MasterStack) Name() string { return "master-stack" }
+
+func (MasterStack) Arrange(items []Item, area Rect, p Params) Plan {
+	return p.Orientation.orient(area, func(area Rect) Plan {
+		return masterStack(items, area, p)
+	})
+}
+
+func masterStack(items []Item, area Rect, p Params) Plan {
+	if len(items) == 0 {
+		return nil
+	}
+
+	masterFrac := clampFrac(p.MasterFrac)
+	nmaster := min(max(p.NMaster, 1), len(items))
+
+	if area.empty() {
+		return nil
+	}
+
+	masterW := int(float64(area.W) * masterFrac)
+	if nmaster == len(items) {
+		masterW = area.W
+	}
+
+	plan := make(Plan, 0, len(items))
+	plan = column(plan, items[:nmaster], Rect{area.X, area.Y, masterW, area.H})
+	plan = column(plan, items[nmaster:], Rect{area.X + masterW, area.Y, area.W - masterW, area.H})
+	return plan
+}
+
+// column splits r top to bottom between items by weight
+func column(plan Plan, items []Item, r Rect) Plan {
+	if len(items) == 0 {
+		return plan
+	}
+
+	y := r.Y
+	for i, h := range weighted(r.H, weights(items)) {
+		plan = append(plan, Placement{ID: items[i].ID, Rect: Rect{r.X, y, r.W, h}})
+		y += h
+	}
+	return plan
+}
@@ -0,0 +1,19 @@
+package layout
+
+// Monocle stacks every window on top of each other, each filling the area,
+// whichever is focused is the one showing
+type Monocle struct{}
+
+func (Monocle) Name() string { return "monocle" }
+
+func (Monocle) Arrange(items []Item, area Rect, p Params) Plan {
+	if len(items) == 0 || area.empty() {
+		return nil
+	}
+
+	plan := make(Plan, 0, len(items))
+	for _, it := range items {
+		plan = append(plan, Placement{ID: it.ID, Rect: area})
+	}
+	return plan
+}
@@ -0,0 +1,72 @@
+package layout
+
+import "fmt"
+
+// Orientation is the side of the area the master goes on. layouts are
+// written for Left, the others are the same geometry turned and mirrored
+type Orientation int
+
+const (
+	OrientLeft Orientation = iota
+	OrientTop
+	OrientRight
+	OrientBottom
+)
+
+// in the order Cycle walks them, clockwise
+var orientationNames = []string{"left", "top", "right", "bottom"}
+
+func ParseOrientation(s string) (Orientation, error) {
+	for i, name := range orientationNames {
+		if s == name {
+			return Orientation(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown orientation %q (want left, right, top or bottom)", s)
+}
+
+func (o Orientation) String() string {
+	if o < 0 || int(o) >= len(orientationNames) {
+		return fmt.Sprintf("Orientation(%d)", int(o))
+	}
+	return orientationNames[o]
+}
+
+// Cycle turns o delta steps clockwise, wrapping around
+func (o Orientation) Cycle(delta int) Orientation {
+	n := len(orientationNames)
+	return Orientation(((int(o)+delta)%n + n) % n)
+}
+
+// Mirror swaps left with right and top with bottom
+func (o Orientation) Mirror() Orientation {
+	return o.Cycle(2)
+}
+
+// orient runs arrange on area as a left oriented layout would see it and
+// maps the result back. top and bottom swap the axes, right and bottom
+// flip them, so odd pixels end up on the mirrored side
+func (o Orientation) orient(area Rect, arrange func(area Rect) Plan) Plan {
+	transpose := o == OrientTop || o == OrientBottom
+	flip := o == OrientRight || o == OrientBottom
+
+	frame := Rect{0, 0, area.W, area.H}
+	if transpose {
+		frame.W, frame.H = frame.H, frame.W
+	}
+
+	plan := arrange(frame)
+	for i := range plan {
+		r := plan[i].Rect
+		if flip {
+			r.X = frame.W - r.X - r.W
+		}
+		if transpose {
+			r = Rect{r.Y, r.X, r.H, r.W}
+		}
+		r.X += area.X
+		r.Y += area.Y
+		plan[i].Rect = r
+	}
+	return plan
+}
@@ -0,0 +1,122 @@
+package layout
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestOrientations(t *testing.T) {
+	// odd sizes and an offset so rounding and the mapping back both show
+	area := Rect{100, 50, 1001, 601}
+	for _, tc := range []struct {
+		o    Orientation
+		want Plan
+	}{
+		{OrientLeft, Plan{
+			{1, Rect{100, 50, 500, 601}},
+			{2, Rect{600, 50, 501, 301}},
+			{3, Rect{600, 351, 501, 300}},
+		}},
+		{OrientRight, Plan{
+			{1, Rect{601, 50, 500, 601}},
+			{2, Rect{100, 50, 501, 301}},
+			{3, Rect{100, 351, 501, 300}},
+		}},
+		{OrientTop, Plan{
+			{1, Rect{100, 50, 1001, 300}},
+			{2, Rect{100, 350, 501, 301}},
+			{3, Rect{601, 350, 500, 301}},
+		}},
+		{OrientBottom, Plan{
+			{1, Rect{100, 351, 1001, 300}},
+			{2, Rect{100, 50, 501, 301}},
+			{3, Rect{601, 50, 500, 301}},
+		}},
+	} {
+		t.Run(tc.o.String(), func(t *testing.T) {
+			got := MasterStack{}.Arrange(items(1, 2, 3), area, Params{MasterFrac: 0.5, Orientation: tc.o})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestTiling checks that the tiling layouts cover odd sized areas exactly,
+// in every orientation, without windows overlapping or leaving a gap
+func TestTiling(t *testing.T) {
+	layouts := []string{"master-stack", "dwindle", "centered-master", "grid", "columns", "rows", "tree"}
+	areas := []Rect{{0, 0, 1000, 600}, {7, 3, 997, 613}, {-1920, 0, 1919, 1079}, {0, 0, 13, 7}}
+	for _, name := range layouts {
+		for o := OrientLeft; o <= OrientBottom; o++ {
+			for _, area := range areas {
+				for n := 1; n <= 7; n++ {
+					l, _ := New(name)
+					ids := make([]uintptr, n)
+					for i := range ids {
+						ids[i] = uintptr(i + 1)
+					}
+					p := Params{MasterFrac: 0.55, NMaster: 1, Orientation: o, Focused: 1}
+					plan := l.Arrange(items(ids...), area, p)
+					if err := tiles(plan, area, n); err != nil {
+						t.Errorf("%s %s %v with %d: %v", name, o, area, n, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// tiles reports how plan fails to split area between n windows
+func tiles(plan Plan, area Rect, n int) error {
+	if len(plan) != n {
+		return fmt.Errorf("%d placements", len(plan))
+	}
+	total := 0
+	for i, a := range plan {
+		r := a.Rect
+		if r.empty() || r.X < area.X || r.Y < area.Y || r.X+r.W > area.X+area.W || r.Y+r.H > area.Y+area.H {
+			return fmt.Errorf("%d at %v is outside the area", a.ID, r)
+		}
+		for _, b := range plan[i+1:] {
+			if overlap(r, b.Rect) {
+				return fmt.Errorf("%d at %v overlaps %d at %v", a.ID, r, b.ID, b.Rect)
+			}
+		}
+		total += r.W * r.H
+	}
+	if total != area.W*area.H {
+		return fmt.Errorf("windows cover %d of %d pixels", total, area.W*area.H)
+	}
+	return nil
+}
+
+func overlap(a, b Rect) bool {
+	return a.X < b.X+b.W && b.X < a.X+a.W && a.Y < b.Y+b.H && b.Y < a.Y+a.H
+}
+
+func TestOrientation(t *testing.T) {
+	for _, name := range orientationNames {
+		o, err := ParseOrientation(name)
+		if err != nil || o.String() != name {
+			t.Errorf("ParseOrientation(%q) = %v, %v", name, o, err)
+		}
+	}
+	if _, err := ParseOrientation("up"); err == nil {
+		t.Error("ParseOrientation accepted up")
+	}
+
+	if o := OrientLeft.Cycle(1); o != OrientTop {
+		t.Errorf("left turned once is %v", o)
+	}
+	if o := OrientLeft.Cycle(-1); o != OrientBottom {
+		t.Errorf("left turned back once is %v", o)
+	}
+	if o := OrientBottom.Cycle(5); o != OrientLeft {
+		t.Errorf("bottom turned five times is %v", o)
+	}
+	if o := OrientTop.Mirror(); o != OrientBottom {
+		t.Errorf("top mirrored is %v", o)
+	}
+}
@@ -0,0 +1,38 @@
+package layout
+
+import (
+	"fmt"
+	"sort"
+)
+
+// layouts can keep per-instance state so the registry hands out constructors
+var registry = map[string]func() Layout{
+	"master-stack":    func() Layout { return MasterStack{} },
+	"dwindle":         func() Layout { return NewDwindle() },
+	"monocle":         func() Layout { return Monocle{} },
+	"centered-master": func() Layout { return CenteredMaster{} },
+	"three-column":    func() Layout { return ThreeColumn{} },
+	"grid":            func() Layout { return Grid{} },
+	"columns":         func() Layout { return Columns{} },
+	"rows":            func() Layout { return Rows{} },
+	"scrolling":       func() Layout { return NewScrolling() },
+	"tree":            func() Layout { return NewTree() },
+}
+
+// New returns a fresh layout by name
+func New(name string) (Layout, error) {
+	f, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown layout %q (have %v)", name, Names())
+	}
+	return f(), nil
+}
+
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
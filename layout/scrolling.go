@@ -0,0 +1,301 @@
+package layout
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColumnWidths are the presets column-width steps through, as fractions of
+// the area's width
+var ColumnWidths = []float64{1.0 / 3, 1.0 / 2, 2.0 / 3}
+
+// Scroller is a layout that puts windows in columns on a strip that can be
+// wider than the area. items are the same ones Arrange gets and every
+// method reports whether it changed anything
+type Scroller interface {
+	Layout
+	// ScrollTo scrolls the strip as little as it takes to bring id's column
+	// into view, view is the area Arrange gets
+	ScrollTo(items []Item, id uintptr, view Rect, p Params) bool
+	// SetColumnWidth makes the column id is in frac of the area wide
+	SetColumnWidth(items []Item, id uintptr, frac float64) bool
+	// ColumnWidth is the width of the column id is in, 0 if id isn't there
+	ColumnWidth(items []Item, id uintptr, p Params) float64
+	// Consume moves id into the bottom of the column on its left
+	Consume(items []Item, id uintptr) bool
+	// Expel takes id out of its column into a new one just to its right.
+	// when windows under id stay behind, id has to follow them in the
+	// workspace order and after is the last of them, otherwise it's 0
+	Expel(items []Item, id uintptr) (after uintptr, ok bool)
+}
+
+// Scrolling lays windows out left to right in columns on an endless strip,
+// the area is a view onto it that only moves with ScrollTo. columns are
+// Params.ColumnWidth wide unless set otherwise and split between the windows
+// in them by weight. columns out of view are left where they are on the
+// strip, outside the area
+type Scrolling struct {
+	// windows that joined another one's column, by the id of the column.
+	// a column's id is the window it started with, it keeps it after that
+	// window is gone. neighbours in the workspace order with the same column
+	// share it
+	column map[uintptr]uintptr
+	// column widths as a fraction of the area, by column id
+	widths map[uintptr]float64
+	// how far the view is scrolled along the strip, in pixels
+	offset int
+}
+
+func NewScrolling() *Scrolling {
+	return &Scrolling{column: make(map[uintptr]uintptr), widths: make(map[uintptr]float64)}
+}
+
+func (*Scrolling) Name() string { return "scrolling" }
+
+// Arrange only reads the offset, columns that closed can leave it past the
+// end of the strip so it's pulled back for the plan
+func (s *Scrolling) Arrange(items []Item, view Rect, p Params) Plan {
+	if len(items) == 0 || view.empty() {
+		return nil
+	}
+
+	cols := s.columns(items)
+	xs := s.strip(cols, view, p)
+	offset := clampOffset(s.offset, xs, view.W)
+
+	plan := make(Plan, 0, len(items))
+	for i, col := range cols {
+		plan = column(plan, col, Rect{view.X + xs[i] - offset, view.Y, xs[i+1] - xs[i], view.H})
+	}
+	return plan
+}
+
+func (s *Scrolling) ScrollTo(items []Item, id uintptr, view Rect, p Params) bool {
+	cols := s.columns(items)
+	c := columnOf(cols, id)
+	if c < 0 || view.empty() {
+		return false
+	}
+
+	xs := s.strip(cols, view, p)
+	offset := clampOffset(scrollTo(clampOffset(s.offset, xs, view.W), xs[c], xs[c+1], view.W), xs, view.W)
+	if offset == s.offset {
+		return false
+	}
+	s.offset = offset
+	return true
+}
+
+// Offset is how far the view is scrolled along the strip
+func (s *Scrolling) Offset() int {
+	return s.offset
+}
+
+// strip is where each of cols starts on the strip, plus where the last one
+// ends
+func (s *Scrolling) strip(cols [][]Item, view Rect, p Params) []int {
+	xs := make([]int, len(cols)+1)
+	for i, col := range cols {
+		xs[i+1] = xs[i] + max(int(float64(view.W)*s.width(s.id(col[0].ID), p)), 1)
+	}
+	return xs
+}
+
+// clampOffset keeps a view w wide from scrolling past either end of the
+// strip xs
+func clampOffset(offset int, xs []int, w int) int {
+	return max(min(offset, xs[len(xs)-1]-w), 0)
+}
+
+// scrollTo moves offset as little as it can so [start, end) is in a view w
+// wide, a column wider than the view is shown from its left edge
+func scrollTo(offset, start, end, w int) int {
+	if end-start >= w || start < offset {
+		return start
+	}
+	if end > offset+w {
+		return end - w
+	}
+	return offset
+}
+
+func (s *Scrolling) SetColumnWidth(items []Item, id uintptr, frac float64) bool {
+	s.prune(items)
+	if index(items, id) < 0 {
+		return false
+	}
+	frac = min(max(frac, 0.1), 1)
+	c := s.id(id)
+	if old, ok := s.widths[c]; ok && old == frac {
+		return false
+	}
+	s.widths[c] = frac
+	return true
+}
+
+func (s *Scrolling) ColumnWidth(items []Item, id uintptr, p Params) float64 {
+	if index(items, id) < 0 {
+		return 0
+	}
+	return s.width(s.id(id), p)
+}
+
+// Consume only takes the top window of a column, the rest of the column
+// stays where it is
+func (s *Scrolling) Consume(items []Item, id uintptr) bool {
+	s.prune(items)
+	i := index(items, id)
+	if i <= 0 || s.id(items[i-1].ID) == s.id(id) {
+		return false
+	}
+	s.detach(items, id)
+	delete(s.widths, id)
+	s.column[id] = s.id(items[i-1].ID)
+	return true
+}
+
+func (s *Scrolling) Expel(items []Item, id uintptr) (uintptr, bool) {
+	s.prune(items)
+	i := index(items, id)
+	if i < 0 {
+		return 0, false
+	}
+
+	c := s.id(id)
+	top, last := i, i
+	for top > 0 && s.id(items[top-1].ID) == c {
+		top--
+	}
+	for last+1 < len(items) && s.id(items[last+1].ID) == c {
+		last++
+	}
+	if top == last {
+		return 0, false
+	}
+
+	s.detach(items, id)
+	if i == top || i == last {
+		return 0, true
+	}
+	return items[last].ID, true
+}
+
+// detach gives id a column of its own id without moving any other window,
+// if others were in id's column the first of them takes it over
+func (s *Scrolling) detach(items []Item, id uintptr) {
+	if _, ok := s.column[id]; ok {
+		delete(s.column, id)
+		return
+	}
+
+	var next uintptr
+	for _, it := range items {
+		if it.ID != id && s.column[it.ID] == id {
+			next = it.ID
+			break
+		}
+	}
+	if next == 0 {
+		return
+	}
+	delete(s.column, next)
+	for w, c := range s.column {
+		if c == id {
+			s.column[w] = next
+		}
+	}
+	if w, ok := s.widths[id]; ok {
+		s.widths[next] = w
+		delete(s.widths, id)
+	}
+}
+
+// columns groups items into columns, in order
+func (s *Scrolling) columns(items []Item) [][]Item {
+	var cols [][]Item
+	for i, it := range items {
+		if i > 0 && s.id(it.ID) == s.id(items[i-1].ID) {
+			cols[len(cols)-1] = append(cols[len(cols)-1], it)
+		} else {
+			cols = append(cols, []Item{it})
+		}
+	}
+	return cols
+}
+
+// id is the id of the column window w is in
+func (s *Scrolling) id(w uintptr) uintptr {
+	if c, ok := s.column[w]; ok {
+		return c
+	}
+	return w
+}
+
+func (s *Scrolling) width(c uintptr, p Params) float64 {
+	if w, ok := s.widths[c]; ok {
+		return w
+	}
+	if p.ColumnWidth > 0 {
+		return p.ColumnWidth
+	}
+	return 0.5
+}
+
+// prune forgets windows that aren't in items any more and the widths of
+// columns with nothing left in them
+func (s *Scrolling) prune(items []Item) {
+	used := make(map[uintptr]bool, len(items))
+	for _, it := range items {
+		used[s.id(it.ID)] = true
+	}
+	for w := range s.column {
+		if !contains(items, w) {
+			delete(s.column, w)
+		}
+	}
+	for c := range s.widths {
+		if !used[c] {
+			delete(s.widths, c)
+		}
+	}
+}
+
+func columnOf(cols [][]Item, id uintptr) int {
+	for c, col := range cols {
+		if contains(col, id) {
+			return c
+		}
+	}
+	return -1
+}
+
+func index(items []Item, id uintptr) int {
+	for i, it := range items {
+		if it.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseWidth reads a column width as a fraction, either a decimal like 0.4
+// or a ratio like 2/3
+func ParseWidth(s string) (float64, error) {
+	var f float64
+	var err error
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		var n, d int
+		if n, err = strconv.Atoi(num); err == nil {
+			if d, err = strconv.Atoi(den); err == nil && d != 0 {
+				f = float64(n) / float64(d)
+			}
+		}
+	} else {
+		f, err = strconv.ParseFloat(s, 64)
+	}
+	if err != nil || f < 0.1 || f > 1 {
+		return 0, fmt.Errorf("column width must be a fraction between 0.1 and 1, got %q", s)
+	}
+	return f, nil
+}
@@ -0,0 +1,145 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+)
+
+func items(ids ...uintptr) []Item {
+	out := make([]Item, len(ids))
+	for i, id := range ids {
+		out[i] = Item{ID: id}
+	}
+	return out
+}
+
+func TestScrollToOffsets(t *testing.T) {
+	view := Rect{0, 0, 1000, 500}
+	all := items(1, 2, 3, 4, 5)
+	s := NewScrolling()
+
+	// half width columns sit 500 apart on the strip
+	for _, step := range []struct {
+		focus  uintptr
+		offset int
+		moved  bool
+	}{
+		{1, 0, false},
+		{2, 0, false},
+		{3, 500, true},
+		{5, 1500, true},
+		{4, 1500, false},
+		{2, 500, true},
+		{1, 0, true},
+	} {
+		moved := s.ScrollTo(all, step.focus, view, Params{})
+		if moved != step.moved || s.Offset() != step.offset {
+			t.Fatalf("focus %d: offset %d moved %v, want %d %v", step.focus, s.Offset(), moved, step.offset, step.moved)
+		}
+	}
+}
+
+func TestScrollToWideColumn(t *testing.T) {
+	view := Rect{0, 0, 1000, 500}
+	all := items(1, 2, 3)
+	s := NewScrolling()
+	s.SetColumnWidth(all, 2, 1)
+
+	s.ScrollTo(all, 3, view, Params{})
+	if s.Offset() != 1000 {
+		t.Fatalf("offset %d, want 1000", s.Offset())
+	}
+	// a column as wide as the view shows from its left edge
+	s.ScrollTo(all, 2, view, Params{})
+	if s.Offset() != 500 {
+		t.Fatalf("offset %d, want 500", s.Offset())
+	}
+}
+
+func TestScrollingArrangeKeepsOffset(t *testing.T) {
+	view := Rect{0, 0, 1000, 500}
+	all := items(1, 2, 3, 4)
+	s := NewScrolling()
+	s.ScrollTo(all, 4, view, Params{})
+
+	want := Plan{
+		{1, Rect{-1000, 0, 500, 500}},
+		{2, Rect{-500, 0, 500, 500}},
+		{3, Rect{0, 0, 500, 500}},
+		{4, Rect{500, 0, 500, 500}},
+	}
+	for range 2 {
+		if got := s.Arrange(all, view, Params{Focused: 1}); !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	if s.Offset() != 1000 {
+		t.Fatalf("Arrange moved the offset to %d", s.Offset())
+	}
+
+	// closing columns pulls the plan back without touching the offset
+	got := s.Arrange(all[:2], view, Params{})
+	want = Plan{{1, Rect{0, 0, 500, 500}}, {2, Rect{500, 0, 500, 500}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestConsumeExpel(t *testing.T) {
+	view := Rect{0, 0, 1000, 600}
+	all := items(1, 2, 3)
+	s := NewScrolling()
+
+	if !s.Consume(all, 2) {
+		t.Fatal("consume 2 did nothing")
+	}
+	if !s.Consume(all, 3) {
+		t.Fatal("consume 3 did nothing")
+	}
+	want := Plan{
+		{1, Rect{0, 0, 500, 200}},
+		{2, Rect{0, 200, 500, 200}},
+		{3, Rect{0, 400, 500, 200}},
+	}
+	if got := s.Arrange(all, view, Params{}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after consume got %v, want %v", got, want)
+	}
+
+	// 2 is in the middle so it has to move after 3 to get a column of its own
+	after, ok := s.Expel(all, 2)
+	if !ok || after != 3 {
+		t.Fatalf("expel 2 = %d %v, want 3 true", after, ok)
+	}
+	all = items(1, 3, 2)
+	want = Plan{
+		{1, Rect{0, 0, 500, 300}},
+		{3, Rect{0, 300, 500, 300}},
+		{2, Rect{500, 0, 500, 600}},
+	}
+	if got := s.Arrange(all, view, Params{}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after expel got %v, want %v", got, want)
+	}
+	if _, ok := s.Expel(all, 2); ok {
+		t.Fatal("expel of a window alone in its column did something")
+	}
+}
+
+func TestParseWidth(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"0.4", 0.4, true},
+		{"2/3", 2.0 / 3, true},
+		{"1", 1, true},
+		{"1/0", 0, false},
+		{"0.05", 0, false},
+		{"wide", 0, false},
+	} {
+		got, err := ParseWidth(tc.in)
+		if (err == nil) != tc.ok || got != tc.want {
+			t.Errorf("ParseWidth(%q) = %v, %v", tc.in, got, err)
+		}
+	}
+}
@@ -0,0 +1,577 @@
+package layout
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Split is how a container shares its rect between its children
+type Split int
+
+const (
+	// side by side, left to right
+	SplitHorizontal Split = iota + 1
+	// top to bottom
+	SplitVertical
+	// every child gets the whole rect and whichever was focused last is on
+	// top, focusing a window is what raises it. with no title bars to draw
+	// tabbed and stacked only differ in which directions step through the
+	// tabs and how move sees them, tabbed as a row and stacked as a column
+	// like i3
+	SplitTabbed
+	SplitStacked
+)
+
+var splitNames = []string{"", "horizontal", "vertical", "tabbed", "stacked"}
+
+func ParseSplit(s string) (Split, error) {
+	switch s {
+	case "h":
+		return SplitHorizontal, nil
+	case "v":
+		return SplitVertical, nil
+	}
+	for i, name := range splitNames[1:] {
+		if s == name {
+			return Split(i + 1), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown split %q (want horizontal, vertical, tabbed or stacked)", s)
+}
+
+func (s Split) String() string {
+	if s <= 0 || int(s) >= len(splitNames) {
+		return fmt.Sprintf("Split(%d)", int(s))
+	}
+	return splitNames[s]
+}
+
+func (s Split) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Split) UnmarshalText(text []byte) error {
+	parsed, err := ParseSplit(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// horizontal is whether the split lines its children up left to right
+func (s Split) horizontal() bool {
+	return s == SplitHorizontal || s == SplitTabbed
+}
+
+// Node is a window or a container of them. a leaf has Window set and no
+// split or children, a container the other way around
+type Node struct {
+	Window uintptr `json:"window,omitempty"`
+	Split  Split   `json:"split,omitempty"`
+	// share of the parent's rect relative to the other children, 0 is 1
+	Weight   float64 `json:"weight,omitempty"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+func (n *Node) leaf() bool {
+	return n.Window != 0
+}
+
+func (n *Node) clone() *Node {
+	c := *n
+	c.Children = make([]*Node, len(n.Children))
+	for i, child := range n.Children {
+		c.Children[i] = child.clone()
+	}
+	if len(c.Children) == 0 {
+		c.Children = nil
+	}
+	return &c
+}
+
+// Arrange places the windows under n that are in weights in r, the rest
+// keep their leaves but get no room. a window's weight scales its node's
+func (n *Node) Arrange(r Rect, weights map[uintptr]float64) Plan {
+	var plan Plan
+	n.arrange(r, weights, &plan)
+	return plan
+}
+
+func (n *Node) arrange(r Rect, weights map[uintptr]float64, plan *Plan) {
+	if n.leaf() {
+		*plan = append(*plan, Placement{ID: n.Window, Rect: r})
+		return
+	}
+
+	var children []*Node
+	for _, c := range n.Children {
+		if c.holds(weights) {
+			children = append(children, c)
+		}
+	}
+	switch n.Split {
+	case SplitHorizontal, SplitVertical:
+		shares := make([]float64, len(children))
+		for i, c := range children {
+			shares[i] = weight(c.Weight)
+			if w := weights[c.Window]; c.leaf() && w > 0 {
+				shares[i] *= w
+			}
+		}
+		if n.Split == SplitHorizontal {
+			x := r.X
+			for i, w := range weighted(r.W, shares) {
+				children[i].arrange(Rect{x, r.Y, w, r.H}, weights, plan)
+				x += w
+			}
+		} else {
+			y := r.Y
+			for i, h := range weighted(r.H, shares) {
+				children[i].arrange(Rect{r.X, y, r.W, h}, weights, plan)
+				y += h
+			}
+		}
+
+	default:
+		for _, c := range children {
+			c.arrange(r, weights, plan)
+		}
+	}
+}
+
+// holds is whether any window under n is in windows
+func (n *Node) holds(windows map[uintptr]float64) bool {
+	if n.leaf() {
+		_, ok := windows[n.Window]
+		return ok
+	}
+	for _, c := range n.Children {
+		if c.holds(windows) {
+			return true
+		}
+	}
+	return false
+}
+
+// find is the leaf holding window, nil if it isn't under n
+func (n *Node) find(window uintptr) *Node {
+	if window == 0 {
+		return nil
+	}
+	if n.Window == window {
+		return n
+	}
+	for _, c := range n.Children {
+		if f := c.find(window); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// path is every node from n down to window's leaf, nil if it isn't there
+func (n *Node) path(window uintptr) []*Node {
+	if window == 0 {
+		return nil
+	}
+	if n.Window == window {
+		return []*Node{n}
+	}
+	for _, c := range n.Children {
+		if p := c.path(window); p != nil {
+			return append([]*Node{n}, p...)
+		}
+	}
+	return nil
+}
+
+// prune drops the leaves keep says no to and the containers that end up
+// empty, reporting whether n itself should go
+func (n *Node) prune(keep func(window uintptr) bool) bool {
+	if n.leaf() {
+		return !keep(n.Window)
+	}
+	n.Children = slices.DeleteFunc(n.Children, func(c *Node) bool { return c.prune(keep) })
+	return len(n.Children) == 0
+}
+
+// Manual is a layout the user builds by hand by splitting containers and
+// moving windows between them. items are the same ones Arrange gets and
+// every method reports whether it changed anything
+type Manual interface {
+	Layout
+	// Split puts id in a new container with split s, so the next window
+	// opened next to it shares its space that way
+	Split(items []Item, id uintptr, s Split) bool
+	// SetSplit changes the split of the container id is in
+	SetSplit(items []Item, id uintptr, s Split) bool
+	// ToggleSplit flips the container id is in between horizontal and
+	// vertical
+	ToggleSplit(items []Item, id uintptr) bool
+	// Move takes id one step in direction d, past its neighbour or into
+	// the container next to it, out of its container at the edge
+	Move(items []Item, id uintptr, d Direction) bool
+	// Tab is the window in the tab next to id's going d, for when id is in
+	// a tabbed container and d is left or right, or a stacked one and d is
+	// up or down. tabs share a rect so going by geometry can't find them
+	Tab(items []Item, id uintptr, d Direction) (uintptr, bool)
+	// Root is a copy of the tree, nil while it's empty
+	Root() *Node
+}
+
+// Tree is i3 style manual tiling: a tree of containers split horizontally,
+// vertically or into tabs with windows as the leaves. new windows open
+// next to the focused one in its container, the workspace order plays no
+// part. containers share their rect by their children's weights
+type Tree struct {
+	root *Node
+	// the last window focused in the tree, where new windows go
+	focus uintptr
+}
+
+func NewTree() *Tree {
+	return &Tree{}
+}
+
+func (*Tree) Name() string { return "tree" }
+
+// Arrange brings the tree up to date with items before laying it out.
+// windows that are gone are dropped and new ones added, the ones in
+// p.Skipped keep their place for when they're back
+func (t *Tree) Arrange(items []Item, area Rect, p Params) Plan {
+	keep := func(w uintptr) bool { return contains(items, w) || slices.Contains(p.Skipped, w) }
+	if t.root != nil && t.root.prune(keep) {
+		t.root = nil
+	}
+	t.sync(items, p.Focused)
+	if t.root == nil || area.empty() {
+		return nil
+	}
+	return t.root.Arrange(area, shown(items))
+}
+
+// sync adds the windows in items the tree doesn't have yet next to the
+// focused one. only Arrange drops windows, it's the one that knows which
+// are gone for good
+func (t *Tree) sync(items []Item, focused uintptr) {
+	if t.root != nil && t.root.find(focused) != nil {
+		t.focus = focused
+	}
+
+	// several new windows go in one after the other
+	anchor := t.focus
+	for _, it := range items {
+		if t.root == nil {
+			t.root = &Node{Split: SplitHorizontal}
+		}
+		if t.root.find(it.ID) != nil {
+			continue
+		}
+		leaf := &Node{Window: it.ID}
+		path := t.root.path(anchor)
+		anchor = it.ID
+		if path == nil {
+			t.root.Children = append(t.root.Children, leaf)
+			continue
+		}
+		parent, at := path[len(path)-2], path[len(path)-1]
+		i := slices.Index(parent.Children, at)
+		parent.Children = slices.Insert(parent.Children, i+1, leaf)
+	}
+}
+
+func (t *Tree) Split(items []Item, id uintptr, s Split) bool {
+	t.sync(items, id)
+	path := t.pathTo(id)
+	if path == nil {
+		return false
+	}
+	parent, leaf := path[len(path)-2], path[len(path)-1]
+	if len(parent.Children) == 1 {
+		// already alone in its container, just change how it splits
+		changed := parent.Split != s
+		parent.Split = s
+		return changed
+	}
+	i := slices.Index(parent.Children, leaf)
+	parent.Children[i] = &Node{Split: s, Weight: leaf.Weight, Children: []*Node{leaf}}
+	leaf.Weight = 0
+	return true
+}
+
+func (t *Tree) SetSplit(items []Item, id uintptr, s Split) bool {
+	t.sync(items, id)
+	path := t.pathTo(id)
+	if path == nil {
+		return false
+	}
+	parent := path[len(path)-2]
+	changed := parent.Split != s
+	parent.Split = s
+	return changed
+}
+
+func (t *Tree) ToggleSplit(items []Item, id uintptr) bool {
+	t.sync(items, id)
+	path := t.pathTo(id)
+	if path == nil {
+		return false
+	}
+	s := SplitHorizontal
+	if path[len(path)-2].Split.horizontal() {
+		s = SplitVertical
+	}
+	return t.SetSplit(items, id, s)
+}
+
+func (t *Tree) Move(items []Item, id uintptr, d Direction) bool {
+	t.sync(items, id)
+	path := t.pathTo(id)
+	if path == nil {
+		return false
+	}
+	leaf := path[len(path)-1]
+	horizontal := d == Left || d == Right
+	forward := d == Right || d == Down
+
+	// find the nearest container lined up the right way with room to go
+	for i := len(path) - 2; i >= 0; i-- {
+		parent, child := path[i], path[i+1]
+		if parent.Split.horizontal() != horizontal {
+			continue
+		}
+		at := slices.Index(parent.Children, child)
+		next := step(parent.Children, at, forward, shown(items))
+		if next < 0 {
+			continue
+		}
+
+		if child == leaf {
+			sibling := parent.Children[next]
+			if sibling.leaf() {
+				parent.Children[at], parent.Children[next] = sibling, leaf
+				return true
+			}
+			// into the container next door, on the side it came from
+			t.detach(path)
+			if forward {
+				sibling.Children = slices.Insert(sibling.Children, 0, leaf)
+			} else {
+				sibling.Children = append(sibling.Children, leaf)
+			}
+			t.tidy()
+			return true
+		}
+
+		// out of its container, next to the one it was in
+		t.detach(path)
+		at = slices.Index(parent.Children, child)
+		if forward {
+			at++
+		}
+		parent.Children = slices.Insert(parent.Children, at, leaf)
+		t.tidy()
+		return true
+	}
+
+	// at the edge of everything, turn the root so there is a side to go to
+	if len(t.root.Children) < 2 || t.root.Split.horizontal() == horizontal {
+		return false
+	}
+	t.detach(path)
+	s := SplitVertical
+	if horizontal {
+		s = SplitHorizontal
+	}
+	old := t.root
+	if len(old.Children) == 1 {
+		old = old.Children[0]
+		old.Weight = 0
+	}
+	t.root = &Node{Split: s, Children: []*Node{old}}
+	if forward {
+		t.root.Children = append(t.root.Children, leaf)
+	} else {
+		t.root.Children = slices.Insert(t.root.Children, 0, leaf)
+	}
+	t.tidy()
+	return true
+}
+
+func (t *Tree) Tab(items []Item, id uintptr, d Direction) (uintptr, bool) {
+	t.sync(items, id)
+	path := t.pathTo(id)
+	windows := shown(items)
+	horizontal := d == Left || d == Right
+	for i := len(path) - 2; i >= 0; i-- {
+		parent, child := path[i], path[i+1]
+		if parent.Split.horizontal() != horizontal {
+			continue
+		}
+		next := step(parent.Children, slices.Index(parent.Children, child), d == Right || d == Down, windows)
+		if next < 0 {
+			continue
+		}
+		if parent.Split != SplitTabbed && parent.Split != SplitStacked {
+			// side by side, geometry finds it
+			return 0, false
+		}
+		return parent.Children[next].first(windows), true
+	}
+	return 0, false
+}
+
+// first is the first window under n that's in windows, 0 if there isn't one
+func (n *Node) first(windows map[uintptr]float64) uintptr {
+	if n.leaf() {
+		if _, ok := windows[n.Window]; ok {
+			return n.Window
+		}
+		return 0
+	}
+	for _, c := range n.Children {
+		if w := c.first(windows); w != 0 {
+			return w
+		}
+	}
+	return 0
+}
+
+// step is the index of the next of children after at with a window showing,
+// -1 if there isn't one
+func step(children []*Node, at int, forward bool, windows map[uintptr]float64) int {
+	d := -1
+	if forward {
+		d = 1
+	}
+	for i := at + d; i >= 0 && i < len(children); i += d {
+		if children[i].holds(windows) {
+			return i
+		}
+	}
+	return -1
+}
+
+// shown is items by id with their weights, what Node.Arrange takes
+func shown(items []Item) map[uintptr]float64 {
+	windows := make(map[uintptr]float64, len(items))
+	for _, it := range items {
+		windows[it.ID] = it.Weight
+	}
+	return windows
+}
+
+// AdjustSplit grows the focused window's share of the nearest container
+// that splits it from its neighbours by delta
+func (t *Tree) AdjustSplit(items []Item, id uintptr, delta float64) (float64, bool) {
+	t.sync(items, id)
+	path := t.pathTo(id)
+	windows := shown(items)
+	for i := len(path) - 2; i >= 0; i-- {
+		parent, child := path[i], path[i+1]
+		if parent.Split != SplitHorizontal && parent.Split != SplitVertical {
+			continue
+		}
+
+		// only the children showing share the room
+		var total float64
+		n := 0
+		for _, c := range parent.Children {
+			if c.holds(windows) {
+				total += weight(c.Weight)
+				n++
+			}
+		}
+		if n < 2 {
+			continue
+		}
+		share := weight(child.Weight) / total
+		next := min(max(share+delta, 0.05), 0.95)
+		if next == share {
+			return delta, false
+		}
+		// keep the siblings' weights and solve for the child's
+		rest := total - weight(child.Weight)
+		child.Weight = next * rest / (1 - next)
+		return 0, true
+	}
+	return delta, false
+}
+
+// Equalize evens out every container's children
+func (t *Tree) Equalize() bool {
+	if t.root == nil {
+		return false
+	}
+	return t.root.equalize()
+}
+
+func (n *Node) equalize() bool {
+	changed := n.Weight != 0
+	n.Weight = 0
+	for _, c := range n.Children {
+		if c.equalize() {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (t *Tree) Root() *Node {
+	if t.root == nil {
+		return nil
+	}
+	return t.root.clone()
+}
+
+func (t *Tree) pathTo(id uintptr) []*Node {
+	if t.root == nil {
+		return nil
+	}
+	return t.root.path(id)
+}
+
+// detach takes the leaf at the end of path out of its container
+func (t *Tree) detach(path []*Node) {
+	parent, leaf := path[len(path)-2], path[len(path)-1]
+	parent.Children = slices.DeleteFunc(parent.Children, func(c *Node) bool { return c == leaf })
+	leaf.Weight = 0
+}
+
+// tidy drops containers left empty, merges containers into parents that
+// split the same way, and drops the root when all it holds is another
+// container
+func (t *Tree) tidy() {
+	if t.root.prune(func(uintptr) bool { return true }) {
+		t.root = nil
+		return
+	}
+	t.root.merge()
+	for len(t.root.Children) == 1 && !t.root.Children[0].leaf() {
+		t.root = t.root.Children[0]
+		t.root.Weight = 0
+	}
+}
+
+// merge splices the children of containers that split the same way as n
+// into n, their weights scaled so they keep the room they had
+func (n *Node) merge() {
+	var children []*Node
+	for _, c := range n.Children {
+		c.merge()
+		if c.leaf() || c.Split != n.Split {
+			children = append(children, c)
+			continue
+		}
+		var total float64
+		for _, g := range c.Children {
+			total += weight(g.Weight)
+		}
+		for _, g := range c.Children {
+			g.Weight = weight(c.Weight) * weight(g.Weight) / total
+			children = append(children, g)
+		}
+	}
+	n.Children = children
+}
@@ -0,0 +1,195 @@
+package layout
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTreeMoveMergesSameSplit(t *testing.T) {
+	area := Rect{0, 0, 1000, 600}
+	all := items(1, 2, 3)
+	tr := NewTree()
+	tr.Arrange(all, area, Params{Focused: 1})
+
+	// h[v[1 2] 3]
+	tr.Split(all, 1, SplitVertical)
+	if !tr.Move(all, 2, Left) {
+		t.Fatal("move 2 left did nothing")
+	}
+	// 3 turns the root, the v[1 2] it wraps mustn't stay a container of
+	// its own inside a v
+	if !tr.Move(all, 3, Down) {
+		t.Fatal("move 3 down did nothing")
+	}
+	root := tr.Root()
+	if root.Split != SplitVertical || len(root.Children) != 3 {
+		t.Fatalf("root is %+v, want a vertical split of the three windows", root)
+	}
+	want := Plan{
+		{1, Rect{0, 0, 1000, 150}},
+		{2, Rect{0, 150, 1000, 150}},
+		{3, Rect{0, 300, 1000, 300}},
+	}
+	if got := tr.Arrange(all, area, Params{Focused: 3}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if tr.Move(all, 3, Down) {
+		t.Fatal("move 3 down at the bottom edge did something")
+	}
+}
+
+func TestTreeSplitAndTabbed(t *testing.T) {
+	area := Rect{0, 0, 900, 600}
+	tr := NewTree()
+	tr.Arrange(items(1, 2), area, Params{Focused: 2})
+	tr.Split(items(1, 2), 2, SplitVertical)
+	// 3 opens next to the focused 2, inside its new container
+	all := items(1, 2, 3)
+	got := tr.Arrange(all, area, Params{Focused: 2})
+	want := Plan{
+		{1, Rect{0, 0, 450, 600}},
+		{2, Rect{450, 0, 450, 300}},
+		{3, Rect{450, 300, 450, 300}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// tabs all get the container's rect
+	tr.SetSplit(all, 2, SplitTabbed)
+	got = tr.Arrange(all, area, Params{Focused: 2})
+	want = Plan{
+		{1, Rect{0, 0, 450, 600}},
+		{2, Rect{450, 0, 450, 600}},
+		{3, Rect{450, 0, 450, 600}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tabbed got %v, want %v", got, want)
+	}
+}
+
+func TestTreeWeightsAndRemainders(t *testing.T) {
+	area := Rect{0, 0, 1000, 600}
+	all := []Item{{ID: 1}, {ID: 2, Weight: 2}, {ID: 3}}
+	tr := NewTree()
+	got := tr.Arrange(all, area, Params{Focused: 1})
+	want := Plan{
+		{1, Rect{0, 0, 250, 600}},
+		{2, Rect{250, 0, 500, 600}},
+		{3, Rect{750, 0, 250, 600}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// 1000 doesn't split in three, the spare pixel goes to the first
+	got = NewTree().Arrange(items(1, 2, 3), area, Params{Focused: 1})
+	want = Plan{
+		{1, Rect{0, 0, 334, 600}},
+		{2, Rect{334, 0, 333, 600}},
+		{3, Rect{667, 0, 333, 600}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestNodeJSON(t *testing.T) {
+	tr := NewTree()
+	all := items(1, 2, 3)
+	tr.Arrange(all, Rect{0, 0, 100, 100}, Params{Focused: 1})
+	tr.Split(all, 3, SplitStacked)
+
+	data, err := json.Marshal(tr.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"split":"horizontal","children":[{"window":1},{"window":2},{"split":"stacked","children":[{"window":3}]}]}`
+	if string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+	var back Node
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&back, tr.Root()) {
+		t.Fatalf("round trip got %+v", back)
+	}
+}
+
+func TestParseSplit(t *testing.T) {
+	for in, want := range map[string]Split{"h": SplitHorizontal, "vertical": SplitVertical, "tabbed": SplitTabbed, "stacked": SplitStacked} {
+		if got, err := ParseSplit(in); err != nil || got != want {
+			t.Errorf("ParseSplit(%q) = %v, %v", in, got, err)
+		}
+	}
+	if _, err := ParseSplit("diagonal"); err == nil {
+		t.Error("ParseSplit accepted diagonal")
+	}
+}
+
+func TestTreeSkipped(t *testing.T) {
+	area := Rect{0, 0, 900, 600}
+	tr := NewTree()
+	tr.Arrange(items(1, 2, 3), area, Params{Focused: 1})
+
+	// 2 is minimized, it keeps its leaf but gets no room
+	shown := items(1, 3)
+	p := Params{Focused: 3, Skipped: []uintptr{2}}
+	got := tr.Arrange(shown, area, p)
+	want := Plan{{1, Rect{0, 0, 450, 600}}, {3, Rect{450, 0, 450, 600}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// moves step over it
+	if !tr.Move(shown, 3, Left) {
+		t.Fatal("move 3 left did nothing")
+	}
+	got = tr.Arrange(shown, area, p)
+	want = Plan{{3, Rect{0, 0, 450, 600}}, {1, Rect{450, 0, 450, 600}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("after the move got %v, want %v", got, want)
+	}
+
+	// and it comes back where it was
+	got = tr.Arrange(items(1, 2, 3), area, Params{Focused: 2})
+	want = Plan{{3, Rect{0, 0, 300, 600}}, {2, Rect{300, 0, 300, 600}}, {1, Rect{600, 0, 300, 600}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("back got %v, want %v", got, want)
+	}
+
+	// a window that isn't skipped either is gone for good
+	tr.Arrange(items(1, 3), area, Params{Focused: 3})
+	if n := len(tr.Root().Children); n != 2 {
+		t.Fatalf("closed window still in the tree, %d children", n)
+	}
+}
+
+func TestTreeTab(t *testing.T) {
+	all := items(1, 2, 3)
+	tr := NewTree()
+	tr.Arrange(all, Rect{0, 0, 900, 600}, Params{Focused: 1})
+	tr.Split(all, 2, SplitTabbed)
+	tr.Move(all, 3, Left)
+	// h[1 tabbed[2 3]]
+	for _, tc := range []struct {
+		id   uintptr
+		d    Direction
+		want uintptr
+		ok   bool
+	}{
+		{2, Right, 3, true},
+		{3, Left, 2, true},
+		{3, Right, 0, false},
+		// beside the tabs geometry takes over
+		{2, Left, 0, false},
+		{2, Down, 0, false},
+		{1, Right, 0, false},
+	} {
+		if got, ok := tr.Tab(all, tc.id, tc.d); got != tc.want || ok != tc.ok {
+			t.Errorf("Tab(%d, %v) = %d, %v, want %d, %v", tc.id, tc.d, got, ok, tc.want, tc.ok)
+		}
+	}
+}
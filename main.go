@@ -3,265 +3,132 @@ package main
 import (
 	"flag"
 	"fmt"
-	"glo/hotkey"
-	"glo/layout"
-	"glo/window"
+	"glo/config"
+	"glo/ipc"
+	"glo/wm"
 	"os"
 	"os/signal"
-	"runtime"
-	"sync"
 	"syscall"
 	"time"
 )
 
 func main() {
-	paddingFlag := flag.Int("padding", 30, "outer padding in pixels")
-	masterFlag := flag.Float64("master", 0.6, "master area fraction (0.1-0.9)")
-	flag.Parse()
-
-	fmt.Println("gloWM - keybinds\nWin+Shift+O: toggle tiling\nWin+Shift+=: grow master\nWin+Shift+-: shrink master\nWin+Shift+.: rotate master\nWin+Shift+Q: quit")
-	screenWidth, screenHeight := window.UsableScreenDimensions()
-
-	user32 := syscall.NewLazyDLL("user32.dll")
-	gfw := user32.NewProc("GetForegroundWindow")
-
-	var windows []*window.Window
-	var mu sync.RWMutex
-	var tilingActive bool
-	var minimized = make(map[uintptr]bool)
-
-	padding := *paddingFlag
-	masterFrac := *masterFlag
-
-	var tileMu sync.Mutex
-	var pending bool
-	var lastTile time.Time
-	triggerTile := func(ws []*window.Window) {
-		tileMu.Lock()
-
-		defer tileMu.Unlock()
-		if !tilingActive {
-			return
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "msg":
+			os.Exit(msg(os.Args[2:]))
+		case "query":
+			os.Exit(query(os.Args[2:]))
 		}
-
-		if time.Since(lastTile) < 40*time.Millisecond {
-			if !pending {
-				pending = true
-				go func() {
-					time.Sleep(50 * time.Millisecond)
-					tileMu.Lock()
-					p := pending
-					pending = false
-					tileMu.Unlock()
-					if p {
-						mu.RLock()
-						snapshot := append([]*window.Window(nil), windows...)
-						mu.RUnlock()
-						layout.TileWindows(snapshot, screenWidth, screenHeight, padding, masterFrac)
-					}
-				}()
-			}
-			return
-		}
-
-		lastTile = time.Now()
-		layout.TileWindows(ws, screenWidth, screenHeight, padding, masterFrac)
 	}
 
-	exitChan := make(chan os.Signal, 1)
-	signal.Notify(exitChan, os.Interrupt, syscall.SIGTERM)
-
-	running := true
+	configFlag := flag.String("config", "", "config file (default %APPDATA%\\glo\\glo.conf)")
+	paddingFlag := flag.Int("padding", 30, "outer gap in pixels on every side, overrides the config")
+	masterFlag := flag.Float64("master", 0.6, "master area fraction (0.1-0.9), overrides the config")
+	adoptFlag := flag.String("adopt", "zorder", "order existing windows are tiled in (zorder, process, position), overrides the config")
+	flag.Parse()
 
-	hotkeyEvents := make(chan int, 32)
-	go func() {
-		runtime.LockOSThread()
-		defer runtime.UnlockOSThread()
+	if _, err := wm.ParseAdoptOrder(*adoptFlag); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
 
-		_ = hotkey.RegisterGlobalHotkey(1, hotkey.MOD_WIN|hotkey.MOD_SHIFT, 0x4F) // toggle
-		_ = hotkey.RegisterGlobalHotkey(2, hotkey.MOD_WIN|hotkey.MOD_SHIFT, 0xBB) // grow master (+)
-		_ = hotkey.RegisterGlobalHotkey(3, hotkey.MOD_WIN|hotkey.MOD_SHIFT, 0xBD) // shrink master (-)
-		_ = hotkey.RegisterGlobalHotkey(4, hotkey.MOD_WIN|hotkey.MOD_SHIFT, 0xBE) // rotate master (.)
-		_ = hotkey.RegisterGlobalHotkey(5, hotkey.MOD_WIN|hotkey.MOD_SHIFT, 0x51) // quit (Q)
+	path := *configFlag
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			fmt.Println("[config]", err)
+		}
+	}
 
-		hotkey.ListenHotkeys(func(id int) {
-			select {
-			case hotkeyEvents <- id:
-			default:
-				// drop
+	// flags given on the command line win over the file, on reload too
+	override := func(cfg *config.Config) {
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "padding":
+				cfg.Gaps = cfg.Gaps.Outer(*paddingFlag)
+			case "master":
+				cfg.MasterFrac = *masterFlag
+			case "adopt":
+				cfg.AdoptOrder, _ = wm.ParseAdoptOrder(*adoptFlag)
 			}
 		})
-	}()
-
-	go func() {
-		for id := range hotkeyEvents {
-			mu.RLock()
-
-			snapshot := make([]*window.Window, 0, len(windows))
-
-			for _, w := range windows {
-				snapshot = append(snapshot, w)
-			}
-
-			mu.RUnlock()
-
-			var visibleSnapshot []*window.Window
-			for _, w := range snapshot {
-				if !w.IsMinimized() {
-					visibleSnapshot = append(visibleSnapshot, w)
-				}
-			}
-
-			switch id {
-			case 1:
-				tilingActive = !tilingActive
-				if tilingActive {
-					layout.TileWindows(visibleSnapshot, screenWidth, screenHeight, padding, masterFrac)
-				} else {
-					for _, w := range visibleSnapshot {
-						w.Restore()
-						w.SetRect(w.Meta.Ox, w.Meta.Oy, w.Meta.Ow, w.Meta.Oh)
-					}
-				}
-			case 2:
-				masterFrac += 0.05
-				if masterFrac > 0.9 {
-					masterFrac = 0.9
-				}
-				triggerTile(visibleSnapshot)
-			case 3:
-				masterFrac -= 0.05
-				if masterFrac < 0.1 {
-					masterFrac = 0.1
-				}
-				triggerTile(visibleSnapshot)
-			case 4:
-				if len(visibleSnapshot) > 1 {
-					mu.Lock()
-					first := windows[0]
-					copy(windows, windows[1:])
-					windows[len(windows)-1] = first
-					mu.Unlock()
+	}
 
-					mu.RLock()
-					updatedSnapshot := append([]*window.Window(nil), windows...)
-					mu.RUnlock()
-					var updatedVisible []*window.Window
-					for _, w := range updatedSnapshot {
-						if !w.IsMinimized() {
-							updatedVisible = append(updatedVisible, w)
-						}
-					}
-					triggerTile(updatedVisible)
-				}
-			case 5:
-				exitChan <- os.Interrupt
-			}
+	cfg := config.Default()
+	if path != "" {
+		loaded, err := config.Load(path)
+		if err != nil {
+			fmt.Printf("[config] %v\n[config] using the built-in defaults\n", err)
+		} else {
+			cfg = loaded
 		}
-	}()
-
-	for running {
-		select {
-		case <-exitChan:
-			mu.RLock()
-			current := append([]*window.Window(nil), windows...)
-			mu.RUnlock()
-			for _, w := range current {
-				w.Restore()
-				w.SetRect(w.Meta.Ox, w.Meta.Oy, w.Meta.Ow, w.Meta.Oh)
-			}
-
-			running = false
-
-		default:
-			hwnd, _, _ := gfw.Call()
-			if hwnd == 0 {
-				time.Sleep(10 * time.Millisecond)
-				continue
-			}
-
-			exists := false
-			mu.RLock()
-			for _, w := range windows {
-				if w.Hwnd() == hwnd {
-					exists = true
-					break
-				}
-			}
-			mu.RUnlock()
-
-			if !exists && window.IsAppWindow(hwnd) && !minimized[hwnd] {
-				w := window.New(hwnd)
-				mu.Lock()
-				windows = append(windows, w)
-				mu.Unlock()
-
-				w.OnMinimize(func() {
-					mu.Lock()
-
-					minimized[w.Hwnd()] = true
-					for i, ww := range windows {
-						if ww.Hwnd() == w.Hwnd() {
-							windows = append(windows[:i], windows[i+1:]...)
-							break
-						}
-					}
-
-					current := append([]*window.Window(nil), windows...)
-					mu.Unlock()
+	}
+	override(cfg)
 
-					if tilingActive {
-						layout.TileWindows(current, screenWidth, screenHeight, padding, masterFrac)
-					}
-				})
+	b, err := nativeBackend()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer b.Close()
 
-				w.OnRestore(func() {
-					mu.Lock()
+	m := wm.New(b, wm.SystemClock(), cfg.Options())
+	bind(m, cfg)
+	m.Adopt()
+	if cfg.TileOnStart {
+		m.Toggle()
+	}
 
-					delete(minimized, w.Hwnd())
-					present := false
-					for _, ww := range windows {
-						if ww.Hwnd() == w.Hwnd() {
-							present = true
-							break
-						}
-					}
+	stop := make(chan struct{})
+	go m.Run(stop)
 
-					if !present {
-						windows = append(windows, w)
-					}
-					current := append([]*window.Window(nil), windows...)
+	srv, err := ipc.Listen(control(m))
+	if err != nil {
+		fmt.Println("[ipc]", err)
+	} else {
+		fmt.Println("[ipc] listening on", ipc.Address())
+		go srv.Serve()
+	}
 
-					mu.Unlock()
-					if tilingActive {
-						layout.TileWindows(current, screenWidth, screenHeight, padding, masterFrac)
-					}
-				})
+	if path != "" {
+		fmt.Println("[config] watching", path)
+		go config.Watch(path, time.Second, stop, func(cfg *config.Config, err error) {
+			if err != nil {
+				fmt.Printf("[config] %v\n[config] keeping the previous config\n", err)
+				return
+			}
+			override(cfg)
+			m.Configure(cfg.Options())
+			bind(m, cfg)
+			fmt.Println("[config] reloaded", path)
+		})
+	}
 
-				w.OnClose(func() {
-					mu.Lock()
-					for i, ww := range windows {
-						if ww.Hwnd() == w.Hwnd() {
-							windows = append(windows[:i], windows[i+1:]...)
-							break
-						}
-					}
-					current := append([]*window.Window(nil), windows...)
-					mu.Unlock()
-					if tilingActive {
-						layout.TileWindows(current, screenWidth, screenHeight, padding, masterFrac)
-					}
-				})
+	exitChan := make(chan os.Signal, 1)
+	signal.Notify(exitChan, os.Interrupt, syscall.SIGTERM)
 
-				if tilingActive {
-					mu.RLock()
-					current := append([]*window.Window(nil), windows...)
-					mu.RUnlock()
-					layout.TileWindows(current, screenWidth, screenHeight, padding, masterFrac)
-				}
-			}
+	select {
+	case <-exitChan:
+	case <-m.Done():
+	}
+	close(stop)
+	if srv != nil {
+		srv.Close()
+	}
+	m.Shutdown()
+}
 
-			time.Sleep(10 * time.Millisecond)
+// bind registers cfg's hotkeys, reporting the ones that couldn't be
+func bind(m *wm.WindowManager, cfg *config.Config) {
+	errs := m.Bind(cfg.WMBindings())
+	fmt.Println("gloWM - keybinds")
+	for i, err := range errs {
+		bnd := cfg.Bindings[i]
+		if err != nil {
+			fmt.Printf("[config] %s: %s: %v\n", bnd.Pos(), bnd.Key, err)
+			continue
 		}
+		fmt.Printf("%s: %s\n", bnd.Key, bnd.Command)
 	}
 }
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"glo/platform"
+	"runtime"
+)
+
+func nativeBackend() (platform.Backend, error) {
+	return nil, fmt.Errorf("glo does not support %s yet", runtime.GOOS)
+}
@@ -0,0 +1,12 @@
+//go:build windows
+
+package main
+
+import (
+	"glo/platform"
+	"glo/platform/win32"
+)
+
+func nativeBackend() (platform.Backend, error) {
+	return win32.New(), nil
+}
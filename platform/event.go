@@ -0,0 +1,49 @@
+package platform
+
+type EventKind int
+
+const (
+	EventCreate EventKind = iota + 1
+	EventDestroy
+	EventShow
+	EventHide
+	EventMinimizeStart
+	EventMinimizeEnd
+	EventForeground
+	// the user let go of a window after dragging or resizing it
+	EventMoveSizeEnd
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventCreate:
+		return "create"
+	case EventDestroy:
+		return "destroy"
+	case EventShow:
+		return "show"
+	case EventHide:
+		return "hide"
+	case EventMinimizeStart:
+		return "minimize-start"
+	case EventMinimizeEnd:
+		return "minimize-end"
+	case EventForeground:
+		return "foreground"
+	case EventMoveSizeEnd:
+		return "move-size-end"
+	}
+	return "unknown"
+}
+
+// Event is something that happened to a top-level window
+type Event struct {
+	Kind EventKind
+	Hwnd uintptr
+}
+
+// EventSource turns OS window notifications into Events on one channel, the
+// channel is closed when the source shuts down
+type EventSource interface {
+	Events() <-chan Event
+}
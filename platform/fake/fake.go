@@ -0,0 +1,446 @@
+package fake
+
+import (
+	"fmt"
+	"glo/platform"
+	"sort"
+	"sync"
+)
+
+// Window is the state the fake keeps for each window
+type Window struct {
+	Title     string
+	Class     string
+	Process   string
+	PID       uint32
+	Style     uint32
+	ExStyle   uint32
+	X, Y      int
+	W, H      int
+	Minimized bool
+	Hidden    bool
+	// NotApp makes IsAppWindow reject the window, e.g. for tool windows
+	NotApp bool
+}
+
+type hotkey struct {
+	modifiers, vk int
+}
+
+// Backend is an in-memory platform.Backend. tests open and close windows on
+// it and press hotkeys, and then look at where things ended up. every change
+// to a window emits the same events the win32 hooks would
+type Backend struct {
+	mu sync.Mutex
+
+	next       uintptr
+	windows    map[uintptr]*Window
+	order      []uintptr // z-order, topmost first
+	foreground uintptr
+
+	monitors []platform.Monitor
+
+	registered map[int]hotkey
+	hotkeys    chan int
+	events     chan platform.Event
+	closed     bool
+}
+
+var _ platform.Backend = (*Backend)(nil)
+
+// New starts with a single primary monitor whose work area is workW x workH
+func New(workW, workH int) *Backend {
+	return &Backend{
+		next:    0x10,
+		windows: make(map[uintptr]*Window),
+		monitors: []platform.Monitor{{
+			Handle: 1, Primary: true,
+			W: workW, H: workH,
+			WorkW: workW, WorkH: workH,
+		}},
+		registered: make(map[int]hotkey),
+		hotkeys:    make(chan int, 32),
+		events:     make(chan platform.Event, 256),
+	}
+}
+
+// Open adds a window on top of everything and gives it focus
+func (b *Backend) Open(w Window) uintptr {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hwnd := b.next
+	b.next += 0x10
+
+	win := w
+	b.windows[hwnd] = &win
+	b.order = append([]uintptr{hwnd}, b.order...)
+	b.emit(platform.EventCreate, hwnd)
+	if !w.Hidden {
+		b.emit(platform.EventShow, hwnd)
+	}
+	if !w.Minimized && !w.Hidden {
+		b.setForeground(hwnd)
+	}
+	return hwnd
+}
+
+// Destroy removes a window as if its process closed it
+func (b *Backend) Destroy(hwnd uintptr) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.windows, hwnd)
+	for i, h := range b.order {
+		if h == hwnd {
+			b.order = append(b.order[:i], b.order[i+1:]...)
+			break
+		}
+	}
+	if b.foreground == hwnd {
+		b.foreground = 0
+	}
+	b.emit(platform.EventDestroy, hwnd)
+}
+
+// Emit queues an event without changing any window, for situations the
+// other helpers can't produce
+func (b *Backend) Emit(ev platform.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.emit(ev.Kind, ev.Hwnd)
+}
+
+func (b *Backend) emit(kind platform.EventKind, hwnd uintptr) {
+	if b.closed {
+		return
+	}
+	select {
+	case b.events <- platform.Event{Kind: kind, Hwnd: hwnd}:
+	default:
+		// drop, same as the real hooks
+	}
+}
+
+func (b *Backend) setForeground(hwnd uintptr) {
+	if b.foreground == hwnd {
+		return
+	}
+	b.foreground = hwnd
+	if hwnd != 0 {
+		b.emit(platform.EventForeground, hwnd)
+	}
+}
+
+// Window returns a copy of the current state of hwnd
+func (b *Backend) Window(hwnd uintptr) (Window, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[hwnd]
+	if !ok {
+		return Window{}, false
+	}
+	return *w, true
+}
+
+// Press fires a registered hotkey, it reports false if id isn't registered
+func (b *Backend) Press(id int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.registered[id]; !ok || b.closed {
+		return false
+	}
+	select {
+	case b.hotkeys <- id:
+	default:
+		// drop, same as the real message loop
+	}
+	return true
+}
+
+// Hotkey returns what id is registered as
+func (b *Backend) Hotkey(id int) (modifiers, vk int, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hk, ok := b.registered[id]
+	return hk.modifiers, hk.vk, ok
+}
+
+// SetMonitors replaces the display setup, the first monitor is the primary
+// one if none is marked
+func (b *Backend) SetMonitors(monitors ...platform.Monitor) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.monitors = append([]platform.Monitor(nil), monitors...)
+	sort.SliceStable(b.monitors, func(i, j int) bool {
+		if b.monitors[i].X != b.monitors[j].X {
+			return b.monitors[i].X < b.monitors[j].X
+		}
+		return b.monitors[i].Y < b.monitors[j].Y
+	})
+}
+
+// Drag moves a window the way a user would, ending with a move-size-end event
+func (b *Backend) Drag(hwnd uintptr, x, y int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[hwnd]
+	if !ok {
+		return fmt.Errorf("no window %#x", hwnd)
+	}
+	w.X, w.Y = x, y
+	b.emit(platform.EventMoveSizeEnd, hwnd)
+	return nil
+}
+
+func (b *Backend) Windows() []uintptr {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]uintptr(nil), b.order...)
+}
+
+func (b *Backend) IsWindow(hwnd uintptr) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, ok := b.windows[hwnd]
+	return ok
+}
+
+func (b *Backend) IsAppWindow(hwnd uintptr) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[hwnd]
+	return ok && !w.NotApp && !w.Hidden && w.Title != ""
+}
+
+func (b *Backend) Info(hwnd uintptr) platform.WindowInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[hwnd]
+	if !ok {
+		return platform.WindowInfo{}
+	}
+	return platform.WindowInfo{
+		Title: w.Title, Class: w.Class, Process: w.Process, PID: w.PID,
+		Style: w.Style, ExStyle: w.ExStyle,
+	}
+}
+
+func (b *Backend) IsMinimized(hwnd uintptr) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[hwnd]
+	return ok && w.Minimized
+}
+
+func (b *Backend) GetRect(hwnd uintptr) (int, int, int, int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[hwnd]
+	if !ok {
+		return 0, 0, 0, 0, fmt.Errorf("no window %#x", hwnd)
+	}
+	return w.X, w.Y, w.W, w.H, nil
+}
+
+func (b *Backend) SetRect(hwnd uintptr, x, y, width, height int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[hwnd]
+	if !ok {
+		return fmt.Errorf("no window %#x", hwnd)
+	}
+	w.X, w.Y, w.W, w.H = x, y, width, height
+	return nil
+}
+
+func (b *Backend) ShowWindow(hwnd uintptr, cmd int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[hwnd]
+	if !ok {
+		return fmt.Errorf("no window %#x", hwnd)
+	}
+
+	switch cmd {
+	case platform.SW_HIDE:
+		b.hide(hwnd, w)
+	case platform.SW_SHOWMINIMIZED:
+		b.show(hwnd, w)
+		if !w.Minimized {
+			w.Minimized = true
+			b.emit(platform.EventMinimizeStart, hwnd)
+		}
+		if b.foreground == hwnd {
+			b.foreground = 0
+		}
+	case platform.SW_SHOWNORMAL, platform.SW_SHOWMAXIMIZED:
+		b.show(hwnd, w)
+		b.unminimize(hwnd, w)
+		b.setForeground(hwnd)
+	case platform.SW_SHOWNOACTIVATE:
+		b.show(hwnd, w)
+		b.unminimize(hwnd, w)
+	case platform.SW_SHOW:
+		b.show(hwnd, w)
+	default:
+		return fmt.Errorf("unknown show command %d", cmd)
+	}
+	return nil
+}
+
+func (b *Backend) show(hwnd uintptr, w *Window) {
+	if w.Hidden {
+		w.Hidden = false
+		b.emit(platform.EventShow, hwnd)
+	}
+}
+
+func (b *Backend) hide(hwnd uintptr, w *Window) {
+	if !w.Hidden {
+		w.Hidden = true
+		b.emit(platform.EventHide, hwnd)
+	}
+	// a hidden window can't keep the focus
+	if b.foreground == hwnd {
+		b.foreground = 0
+	}
+}
+
+func (b *Backend) unminimize(hwnd uintptr, w *Window) {
+	if w.Minimized {
+		w.Minimized = false
+		b.emit(platform.EventMinimizeEnd, hwnd)
+	}
+}
+
+func (b *Backend) Foreground() uintptr {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.foreground
+}
+
+func (b *Backend) Focus(hwnd uintptr) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.windows[hwnd]; !ok {
+		return fmt.Errorf("no window %#x", hwnd)
+	}
+	b.setForeground(hwnd)
+	for i, h := range b.order {
+		if h == hwnd {
+			copy(b.order[1:i+1], b.order[:i])
+			b.order[0] = hwnd
+			break
+		}
+	}
+	return nil
+}
+
+func (b *Backend) Monitors() []platform.Monitor {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := append([]platform.Monitor(nil), b.monitors...)
+	if len(out) > 0 {
+		primary := false
+		for _, m := range out {
+			primary = primary || m.Primary
+		}
+		out[0].Primary = out[0].Primary || !primary
+	}
+	return out
+}
+
+func (b *Backend) MonitorFromWindow(hwnd uintptr) uintptr {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[hwnd]
+	if !ok || len(b.monitors) == 0 {
+		return 0
+	}
+
+	// most overlap wins, otherwise the monitor whose centre is closest
+	best, bestArea := uintptr(0), 0
+	for _, m := range b.monitors {
+		ix := min(w.X+w.W, m.X+m.W) - max(w.X, m.X)
+		iy := min(w.Y+w.H, m.Y+m.H) - max(w.Y, m.Y)
+		if ix > 0 && iy > 0 && ix*iy > bestArea {
+			best, bestArea = m.Handle, ix*iy
+		}
+	}
+	if best != 0 {
+		return best
+	}
+
+	cx, cy := w.X+w.W/2, w.Y+w.H/2
+	bestDist := -1
+	for _, m := range b.monitors {
+		dx, dy := cx-(m.X+m.W/2), cy-(m.Y+m.H/2)
+		if d := dx*dx + dy*dy; bestDist < 0 || d < bestDist {
+			best, bestDist = m.Handle, d
+		}
+	}
+	return best
+}
+
+func (b *Backend) RegisterHotkey(id, modifiers, vk int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for other, hk := range b.registered {
+		if other != id && hk.modifiers == modifiers && hk.vk == vk {
+			return fmt.Errorf("hotkey already registered")
+		}
+	}
+	b.registered[id] = hotkey{modifiers, vk}
+	return nil
+}
+
+func (b *Backend) UnregisterHotkey(id int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.registered[id]; !ok {
+		return fmt.Errorf("hotkey %d not registered", id)
+	}
+	delete(b.registered, id)
+	return nil
+}
+
+func (b *Backend) Hotkeys() <-chan int {
+	return b.hotkeys
+}
+
+func (b *Backend) Events() <-chan platform.Event {
+	return b.events
+}
+
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.closed {
+		b.closed = true
+		close(b.hotkeys)
+		close(b.events)
+	}
+	return nil
+}
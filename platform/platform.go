@@ -0,0 +1,62 @@
+package platform
+
+// show commands, same values as the win32 SW_* constants
+const (
+	SW_HIDE           = 0
+	SW_SHOWNORMAL     = 1
+	SW_SHOWMINIMIZED  = 2
+	SW_SHOWMAXIMIZED  = 3
+	SW_SHOWNOACTIVATE = 4
+	SW_SHOW           = 5
+)
+
+type WindowInfo struct {
+	Title   string
+	Class   string
+	Process string // lowercased executable name, e.g. "code.exe"
+	PID     uint32
+	// raw GWL_STYLE and GWL_EXSTYLE bits
+	Style, ExStyle uint32
+}
+
+// Monitor is one display, X/Y/W/H cover the whole screen and the Work
+// fields the part not taken up by the taskbar
+type Monitor struct {
+	Handle  uintptr
+	Primary bool
+
+	X, Y, W, H                 int
+	WorkX, WorkY, WorkW, WorkH int
+}
+
+// Backend is everything glo needs from the OS. the win32 package is the real
+// implementation and the fake package is an in-memory one for tests
+type Backend interface {
+	// top-level windows in z-order, topmost first
+	Windows() []uintptr
+	IsWindow(hwnd uintptr) bool
+	IsAppWindow(hwnd uintptr) bool
+	IsMinimized(hwnd uintptr) bool
+	Info(hwnd uintptr) WindowInfo
+
+	GetRect(hwnd uintptr) (x, y, width, height int, err error)
+	SetRect(hwnd uintptr, x, y, width, height int) error
+	ShowWindow(hwnd uintptr, cmd int) error
+
+	Foreground() uintptr
+	Focus(hwnd uintptr) error
+
+	// monitors sorted left to right, then top to bottom
+	Monitors() []Monitor
+	// the monitor hwnd is mostly on, or the nearest one if it is off screen
+	MonitorFromWindow(hwnd uintptr) uintptr
+
+	RegisterHotkey(id, modifiers, vk int) error
+	UnregisterHotkey(id int) error
+	// hotkey ids as they are pressed, closed when the backend is closed
+	Hotkeys() <-chan int
+
+	EventSource
+
+	Close() error
+}
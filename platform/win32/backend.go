@@ -0,0 +1,174 @@
+//go:build windows
+
+package win32
+
+import (
+	"fmt"
+	"glo/platform"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32                 = syscall.NewLazyDLL("user32.dll")
+	registerHotKey         = user32.NewProc("RegisterHotKey")
+	unregisterHotKey       = user32.NewProc("UnregisterHotKey")
+	getMessage             = user32.NewProc("GetMessageW")
+	peekMessage            = user32.NewProc("PeekMessageW")
+	postThreadMessage      = user32.NewProc("PostThreadMessageW")
+	procGetCurrentThreadId = kernel32.NewProc("GetCurrentThreadId")
+)
+
+const (
+	WM_QUIT      = 0x0012
+	WM_HOTKEY    = 0x0312
+	WM_USER      = 0x0400
+	WM_APP       = 0x8000
+	PM_NOREMOVE  = 0x0000
+	hotkeyBuffer = 32
+)
+
+type msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+}
+
+// Backend talks to user32 directly. hotkeys and win event hooks are tied to
+// the thread that pumps messages so everything thread-bound runs on one
+// locked goroutine and other goroutines hand work to it with call
+type Backend struct {
+	threadID uintptr
+	calls    chan func()
+	hotkeys  chan int
+	events   chan platform.Event
+	ready    chan struct{}
+}
+
+func New() *Backend {
+	b := &Backend{
+		calls:   make(chan func(), 16),
+		hotkeys: make(chan int, hotkeyBuffer),
+		events:  make(chan platform.Event, eventBuffer),
+		ready:   make(chan struct{}),
+	}
+	go b.loop()
+	<-b.ready
+	return b
+}
+
+func (b *Backend) loop() {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	b.threadID, _, _ = procGetCurrentThreadId.Call()
+
+	// make sure the thread has a message queue before anyone posts to it
+	var m msg
+	peekMessage.Call(uintptr(unsafe.Pointer(&m)), 0, WM_USER, WM_USER, PM_NOREMOVE)
+
+	hooks := b.installHooks()
+	close(b.ready)
+
+	defer close(b.events)
+	defer close(b.hotkeys)
+	defer b.removeHooks(hooks)
+	for {
+		r, _, err := getMessage.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if r == 0 {
+			//fmt.Println("[win32] message loop exiting (WM_QUIT)")
+			return
+		}
+		if r == ^uintptr(0) { // -1
+			if err == syscall.Errno(0) {
+				err = syscall.GetLastError()
+			}
+
+			fmt.Printf("[win32] GetMessageW error: %v\n", err)
+			continue
+		}
+
+		switch m.message {
+		case WM_HOTKEY:
+			select {
+			case b.hotkeys <- int(m.wParam):
+			default:
+				// drop
+			}
+		case WM_APP:
+			b.drain()
+		}
+	}
+}
+
+func (b *Backend) drain() {
+	for {
+		select {
+		case f := <-b.calls:
+			f()
+		default:
+			return
+		}
+	}
+}
+
+// call runs f on the message loop thread and waits for it
+func (b *Backend) call(f func()) {
+	done := make(chan struct{})
+	b.calls <- func() {
+		f()
+		close(done)
+	}
+	postThreadMessage.Call(b.threadID, WM_APP, 0, 0)
+	<-done
+}
+
+func (b *Backend) RegisterHotkey(id, modifiers, vk int) error {
+	var err error
+	b.call(func() {
+		r, _, e := registerHotKey.Call(0, uintptr(id), uintptr(modifiers), uintptr(vk))
+		if r == 0 {
+			if e == syscall.Errno(0) {
+				e = syscall.GetLastError()
+			}
+			err = e
+		}
+	})
+	if err != nil {
+		// the caller knows which binding this was and reports it
+		return err
+	}
+	//fmt.Printf("[hotkey] registered hotkey id=%d mod=%d vk=%d\n", id, modifiers, vk)
+
+	return nil
+}
+
+func (b *Backend) UnregisterHotkey(id int) error {
+	var err error
+	b.call(func() {
+		r, _, e := unregisterHotKey.Call(0, uintptr(id))
+		if r == 0 {
+			if e == syscall.Errno(0) {
+				e = syscall.GetLastError()
+			}
+			err = e
+		}
+	})
+	return err
+}
+
+func (b *Backend) Hotkeys() <-chan int {
+	return b.hotkeys
+}
+
+func (b *Backend) Close() error {
+	r, _, err := postThreadMessage.Call(b.threadID, WM_QUIT, 0, 0)
+	if r == 0 {
+		return fmt.Errorf("PostThreadMessageW failed: %v", err)
+	}
+	return nil
+}
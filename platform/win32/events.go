@@ -0,0 +1,129 @@
+//go:build windows
+
+package win32
+
+import (
+	"fmt"
+	"glo/platform"
+	"sync"
+	"syscall"
+)
+
+var (
+	procSetWinEventHook = user32.NewProc("SetWinEventHook")
+	procUnhookWinEvent  = user32.NewProc("UnhookWinEvent")
+	procGetAncestor     = user32.NewProc("GetAncestor")
+)
+
+const (
+	EVENT_SYSTEM_FOREGROUND    = 0x0003
+	EVENT_SYSTEM_MOVESIZEEND   = 0x000B
+	EVENT_SYSTEM_MINIMIZESTART = 0x0016
+	EVENT_SYSTEM_MINIMIZEEND   = 0x0017
+	EVENT_OBJECT_CREATE        = 0x8000
+	EVENT_OBJECT_DESTROY       = 0x8001
+	EVENT_OBJECT_SHOW          = 0x8002
+	EVENT_OBJECT_HIDE          = 0x8003
+	WINEVENT_OUTOFCONTEXT      = 0x0000
+	WINEVENT_SKIPOWNPROCESS    = 0x0002
+	OBJID_WINDOW               = 0
+	CHILDID_SELF               = 0
+	GA_ROOT                    = 2
+	eventBuffer                = 1024
+)
+
+var eventKinds = map[uintptr]platform.EventKind{
+	EVENT_SYSTEM_FOREGROUND:    platform.EventForeground,
+	EVENT_SYSTEM_MOVESIZEEND:   platform.EventMoveSizeEnd,
+	EVENT_SYSTEM_MINIMIZESTART: platform.EventMinimizeStart,
+	EVENT_SYSTEM_MINIMIZEEND:   platform.EventMinimizeEnd,
+	EVENT_OBJECT_CREATE:        platform.EventCreate,
+	EVENT_OBJECT_DESTROY:       platform.EventDestroy,
+	EVENT_OBJECT_SHOW:          platform.EventShow,
+	EVENT_OBJECT_HIDE:          platform.EventHide,
+}
+
+// win event callbacks can't carry a context pointer, so the single callback
+// forwards to whichever backend installed the hooks
+var (
+	hookMu      sync.Mutex
+	hookBackend *Backend
+	hookProc    = syscall.NewCallback(winEventProc)
+)
+
+func winEventProc(hook, event, hwnd, idObject, idChild, thread, ms uintptr) uintptr {
+	if hwnd == 0 || int32(idObject) != OBJID_WINDOW || int32(idChild) != CHILDID_SELF {
+		return 0
+	}
+
+	kind, ok := eventKinds[event]
+	if !ok {
+		return 0
+	}
+
+	// object events fire for every child control too, only top-level windows matter.
+	// a destroyed window can't be asked for its ancestor any more so let those through
+	if kind != platform.EventDestroy {
+		root, _, _ := procGetAncestor.Call(hwnd, GA_ROOT)
+		if root != hwnd {
+			return 0
+		}
+	}
+
+	hookMu.Lock()
+	b := hookBackend
+	hookMu.Unlock()
+	if b == nil {
+		return 0
+	}
+
+	select {
+	case b.events <- platform.Event{Kind: kind, Hwnd: hwnd}:
+	default:
+		fmt.Printf("[win32] event queue full, dropped %v for %#x\n", kind, hwnd)
+	}
+	return 0
+}
+
+// installHooks must run on the message loop thread, that is where the
+// out-of-context callbacks get delivered
+func (b *Backend) installHooks() []uintptr {
+	hookMu.Lock()
+	hookBackend = b
+	hookMu.Unlock()
+
+	// not up to EVENT_OBJECT_LOCATIONCHANGE, that fires for every pixel of
+	// a drag or animation and would flood the queue. MOVESIZEEND says when
+	// a drag is over, which is all glo needs
+	ranges := [][2]uintptr{
+		{EVENT_SYSTEM_FOREGROUND, EVENT_SYSTEM_MINIMIZEEND},
+		{EVENT_OBJECT_CREATE, EVENT_OBJECT_HIDE},
+	}
+
+	var hooks []uintptr
+	for _, r := range ranges {
+		h, _, err := procSetWinEventHook.Call(r[0], r[1], 0, hookProc, 0, 0, WINEVENT_OUTOFCONTEXT|WINEVENT_SKIPOWNPROCESS)
+		if h == 0 {
+			fmt.Printf("[win32] SetWinEventHook(%#x-%#x) failed: %v\n", r[0], r[1], err)
+			continue
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks
+}
+
+func (b *Backend) removeHooks(hooks []uintptr) {
+	for _, h := range hooks {
+		procUnhookWinEvent.Call(h)
+	}
+
+	hookMu.Lock()
+	if hookBackend == b {
+		hookBackend = nil
+	}
+	hookMu.Unlock()
+}
+
+func (b *Backend) Events() <-chan platform.Event {
+	return b.events
+}
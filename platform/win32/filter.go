@@ -1,4 +1,6 @@
-package window
+//go:build windows
+
+package win32
 
 import (
 	"strings"
@@ -12,6 +14,7 @@ var (
 	procIsWindowVisible     = user32.NewProc("IsWindowVisible")
 	procGetWindow           = user32.NewProc("GetWindow")
 	procGetWindowTextLength = user32.NewProc("GetWindowTextLengthW")
+	procGetWindowTextW      = user32.NewProc("GetWindowTextW")
 	procGetClassNameW       = user32.NewProc("GetClassNameW")
 	procGetWindowRect       = user32.NewProc("GetWindowRect")
 	dwmapi                  = syscall.NewLazyDLL("dwmapi.dll")
@@ -32,8 +35,6 @@ const (
 	WS_CHILD                   = 0x40000000
 	WS_EX_TOOLWINDOW           = 0x00000080
 	WS_EX_APPWINDOW            = 0x00040000
-	WS_EX_TOPMOST              = 0x00000008
-	WS_EX_LAYERED              = 0x00080000
 	GW_OWNER                   = 4
 	DWMWA_CLOAKED              = 14
 	PROCESS_QUERY_LIMITED_INFO = 0x1000
@@ -143,15 +144,30 @@ func windowTitleLength(hwnd uintptr) int {
 	return int(r)
 }
 
+func getWindowTitle(hwnd uintptr) string {
+	n := windowTitleLength(hwnd)
+	if n == 0 {
+		return ""
+	}
+	buf := make([]uint16, n+1)
+	r, _, _ := procGetWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	return syscall.UTF16ToString(buf[:r])
+}
+
 func getClassName(hwnd uintptr) string {
 	buf := make([]uint16, 256)
 	n, _, _ := procGetClassNameW.Call(hwnd, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
 	return syscall.UTF16ToString(buf[:n])
 }
 
-func getProcessName(hwnd uintptr) string {
+func getPID(hwnd uintptr) uint32 {
 	var pid uint32
 	procGetWindowThreadPID.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	return pid
+}
+
+func getProcessName(hwnd uintptr) string {
+	pid := getPID(hwnd)
 	if pid == 0 {
 		return ""
 	}
@@ -176,13 +192,6 @@ func getProcessName(hwnd uintptr) string {
 	return strings.ToLower(full)
 }
 
-func getWindowSize(hwnd uintptr) (int, int) {
-	var r struct{ Left, Top, Right, Bottom int32 }
-
-	procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&r)))
-	return int(r.Right - r.Left), int(r.Bottom - r.Top)
-}
-
 func IsAppWindow(hwnd uintptr) bool {
 	if !isWindowVisible(hwnd) {
 		return false
@@ -209,42 +218,20 @@ func IsAppWindow(hwnd uintptr) bool {
 		return false
 	}
 
+	// the desktop and the start button are never app windows, not even after
+	// unrule all
 	cls := getClassName(hwnd)
 	if cls == "Progman" || cls == "Button" {
 		return false
 	}
 
-	procName := getProcessName(hwnd)
-
-	// exclude system stuff
-	if procName == "snippingtool.exe" || procName == "searchhost.exe" || procName == "screenclippinghost.exe" || procName == "applicationframehost.exe" || procName == "shellexperiencehost.exe" {
-		return false
-	}
-
-	if strings.Contains(strings.ToLower(cls), "snip") || strings.Contains(strings.ToLower(cls), "clipping") {
-		return false
-	}
-
-	if (exStyle&WS_EX_TOPMOST) != 0 && (exStyle&WS_EX_LAYERED) != 0 {
-		w, h := getWindowSize(hwnd)
-		if h <= 100 && w < 800 {
-			return false
-		}
-	}
-
-	if procName == "explorer.exe" {
-		lc := strings.ToLower(cls)
-		if lc != "cabinetwclass" { // allow only main explorer windows and not stuff like the run window
-			return false
-		}
-	}
+	// particular apps (snipping tool, search, explorer's run box...) are left
+	// out by the default rules instead, see config/default.conf
 
 	if isHigherLevelProcess(hwnd) {
 		return false
 	}
 
-	// fmt.Println(procName)
-
 	return true
 }
 
@@ -0,0 +1,82 @@
+//go:build windows
+
+package win32
+
+import (
+	"glo/platform"
+	"sort"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procEnumDisplayMonitors = user32.NewProc("EnumDisplayMonitors")
+	procGetMonitorInfoW     = user32.NewProc("GetMonitorInfoW")
+	procMonitorFromWindow   = user32.NewProc("MonitorFromWindow")
+)
+
+const (
+	MONITOR_DEFAULTTONEAREST = 2
+	MONITORINFOF_PRIMARY     = 1
+)
+
+type monitorInfo struct {
+	cbSize    uint32
+	rcMonitor rect
+	rcWork    rect
+	dwFlags   uint32
+}
+
+// same single-callback arrangement as EnumWindows
+var (
+	monitorMu   sync.Mutex
+	monitorBuf  []uintptr
+	monitorProc = syscall.NewCallback(func(hMonitor, hdc, lprc, lparam uintptr) uintptr {
+		monitorBuf = append(monitorBuf, hMonitor)
+		return 1
+	})
+)
+
+func (b *Backend) Monitors() []platform.Monitor {
+	monitorMu.Lock()
+	monitorBuf = nil
+	procEnumDisplayMonitors.Call(0, 0, monitorProc, 0)
+	handles := monitorBuf
+	monitorBuf = nil
+	monitorMu.Unlock()
+
+	var out []platform.Monitor
+	for _, h := range handles {
+		mi := monitorInfo{cbSize: uint32(unsafe.Sizeof(monitorInfo{}))}
+		r, _, _ := procGetMonitorInfoW.Call(h, uintptr(unsafe.Pointer(&mi)))
+		if r == 0 {
+			continue
+		}
+		out = append(out, platform.Monitor{
+			Handle:  h,
+			Primary: mi.dwFlags&MONITORINFOF_PRIMARY != 0,
+			X:       int(mi.rcMonitor.Left),
+			Y:       int(mi.rcMonitor.Top),
+			W:       int(mi.rcMonitor.Right - mi.rcMonitor.Left),
+			H:       int(mi.rcMonitor.Bottom - mi.rcMonitor.Top),
+			WorkX:   int(mi.rcWork.Left),
+			WorkY:   int(mi.rcWork.Top),
+			WorkW:   int(mi.rcWork.Right - mi.rcWork.Left),
+			WorkH:   int(mi.rcWork.Bottom - mi.rcWork.Top),
+		})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].X != out[j].X {
+			return out[i].X < out[j].X
+		}
+		return out[i].Y < out[j].Y
+	})
+	return out
+}
+
+func (b *Backend) MonitorFromWindow(hwnd uintptr) uintptr {
+	h, _, _ := procMonitorFromWindow.Call(hwnd, MONITOR_DEFAULTTONEAREST)
+	return h
+}
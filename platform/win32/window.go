@@ -0,0 +1,128 @@
+//go:build windows
+
+package win32
+
+import (
+	"fmt"
+	"glo/platform"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procIsIconic            = user32.NewProc("IsIconic")
+	procIsWindow            = user32.NewProc("IsWindow")
+	procMoveWindow          = user32.NewProc("MoveWindow")
+	procShowWindow          = user32.NewProc("ShowWindow")
+	procEnumWindows         = user32.NewProc("EnumWindows")
+	procGetForegroundWindow = user32.NewProc("GetForegroundWindow")
+	procSetForegroundWindow = user32.NewProc("SetForegroundWindow")
+	procKeybdEvent          = user32.NewProc("keybd_event")
+)
+
+const (
+	VK_MENU         = 0x12
+	KEYEVENTF_KEYUP = 0x0002
+)
+
+type rect struct {
+	Left, Top, Right, Bottom int32
+}
+
+// EnumWindows needs a callback, and callbacks are a limited resource so
+// there is only ever one and it appends to enumBuf under enumMu
+var (
+	enumMu   sync.Mutex
+	enumBuf  []uintptr
+	enumProc = syscall.NewCallback(func(hwnd, _ uintptr) uintptr {
+		enumBuf = append(enumBuf, hwnd)
+		return 1
+	})
+)
+
+func (b *Backend) Windows() []uintptr {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+
+	enumBuf = nil
+	procEnumWindows.Call(enumProc, 0)
+	out := enumBuf
+	enumBuf = nil
+	return out
+}
+
+func (b *Backend) IsWindow(hwnd uintptr) bool {
+	r, _, _ := procIsWindow.Call(hwnd)
+	return r != 0
+}
+
+func (b *Backend) IsAppWindow(hwnd uintptr) bool {
+	return IsAppWindow(hwnd)
+}
+
+func (b *Backend) Info(hwnd uintptr) platform.WindowInfo {
+	return platform.WindowInfo{
+		Title:   getWindowTitle(hwnd),
+		Class:   getClassName(hwnd),
+		Process: getProcessName(hwnd),
+		PID:     getPID(hwnd),
+		Style:   uint32(getWindowLongPtr(hwnd, GWL_STYLE)),
+		ExStyle: uint32(getWindowLongPtr(hwnd, GWL_EXSTYLE)),
+	}
+}
+
+func (b *Backend) IsMinimized(hwnd uintptr) bool {
+	return isIconic(hwnd)
+}
+
+func (b *Backend) GetRect(hwnd uintptr) (int, int, int, int, error) {
+	var r rect
+	ok, _, _ := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&r)))
+	if ok == 0 {
+		return 0, 0, 0, 0, fmt.Errorf("GetWindowRect failed")
+	}
+
+	return int(r.Left), int(r.Top), int(r.Right - r.Left), int(r.Bottom - r.Top), nil
+}
+
+func (b *Backend) SetRect(hwnd uintptr, x, y, width, height int) error {
+	r, _, _ := procMoveWindow.Call(hwnd, uintptr(x), uintptr(y), uintptr(width), uintptr(height), 1)
+	if r == 0 {
+		return fmt.Errorf("MoveWindow failed")
+	}
+	return nil
+}
+
+func (b *Backend) ShowWindow(hwnd uintptr, cmd int) error {
+	// the return value is whether the window was visible before, not success
+	procShowWindow.Call(hwnd, uintptr(cmd))
+	return nil
+}
+
+func (b *Backend) Foreground() uintptr {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	return hwnd
+}
+
+func (b *Backend) Focus(hwnd uintptr) error {
+	r, _, _ := procSetForegroundWindow.Call(hwnd)
+	if r != 0 {
+		return nil
+	}
+
+	// only the foreground process may steal focus, a fake alt tap gets around that
+	procKeybdEvent.Call(VK_MENU, 0, 0, 0)
+	procKeybdEvent.Call(VK_MENU, 0, KEYEVENTF_KEYUP, 0)
+
+	r, _, _ = procSetForegroundWindow.Call(hwnd)
+	if r == 0 {
+		return fmt.Errorf("SetForegroundWindow failed")
+	}
+	return nil
+}
+
+func isIconic(hwnd uintptr) bool {
+	r, _, _ := procIsIconic.Call(hwnd)
+	return r != 0
+}
@@ -0,0 +1,173 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type cond interface {
+	matches(w Window) bool
+}
+
+// parseCond reads one condition. ok is false if field isn't shaped like a
+// condition at all, which is where the actions start
+func parseCond(field string) (c cond, ok bool, err error) {
+	key, op, value := splitCond(field)
+	if op == "" {
+		return nil, false, nil
+	}
+
+	switch key {
+	case "process", "class", "title":
+		if op != "=" && op != "!=" {
+			return nil, true, fmt.Errorf("%s takes = or !=, got %q", key, op)
+		}
+		m, err := match(value)
+		if err != nil {
+			return nil, true, fmt.Errorf("%s: %v", key, err)
+		}
+		return textCond{key, m, op == "!="}, true, nil
+
+	case "style", "exstyle":
+		if op != "=" && op != "!=" {
+			return nil, true, fmt.Errorf("%s takes = or !=, got %q", key, op)
+		}
+		bits, err := parseFlags(value, key == "exstyle")
+		if err != nil {
+			return nil, true, err
+		}
+		return styleCond{key == "exstyle", bits, op == "!="}, true, nil
+
+	case "width", "height":
+		if op == "!=" {
+			return nil, true, fmt.Errorf("%s takes <, <=, =, >= or >", key)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, true, fmt.Errorf("%s wants a number of pixels, got %q", key, value)
+		}
+		return sizeCond{key == "height", op, n}, true, nil
+	}
+	return nil, true, fmt.Errorf("unknown rule condition %q", key)
+}
+
+var condOps = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+func splitCond(field string) (key, op, value string) {
+	i := strings.IndexAny(field, "!=<>")
+	if i <= 0 {
+		return "", "", ""
+	}
+	for _, op := range condOps {
+		if strings.HasPrefix(field[i:], op) {
+			return field[:i], op, field[i+len(op):]
+		}
+	}
+	return "", "", ""
+}
+
+type textCond struct {
+	field  string
+	m      matcher
+	negate bool
+}
+
+func (c textCond) matches(w Window) bool {
+	var s string
+	switch c.field {
+	case "process":
+		s = w.Process
+	case "class":
+		s = w.Class
+	case "title":
+		s = w.Title
+	}
+	return c.m.match(s) != c.negate
+}
+
+type styleCond struct {
+	ex     bool
+	bits   uint32
+	negate bool
+}
+
+func (c styleCond) matches(w Window) bool {
+	style := w.Style
+	if c.ex {
+		style = w.ExStyle
+	}
+	if c.negate {
+		return style&c.bits == 0
+	}
+	return style&c.bits == c.bits
+}
+
+type sizeCond struct {
+	height bool
+	op     string
+	n      int
+}
+
+func (c sizeCond) matches(w Window) bool {
+	v := w.W
+	if c.height {
+		v = w.H
+	}
+	switch c.op {
+	case "<":
+		return v < c.n
+	case "<=":
+		return v <= c.n
+	case "=":
+		return v == c.n
+	case ">=":
+		return v >= c.n
+	case ">":
+		return v > c.n
+	}
+	return false
+}
+
+// matcher compares text case insensitively. a value starting with re: is a
+// regular expression, one with * or ? in it is a glob, anything else has
+// to match exactly
+type matcher struct {
+	exact string
+	re    *regexp.Regexp
+}
+
+func match(value string) (matcher, error) {
+	if expr, ok := strings.CutPrefix(value, "re:"); ok {
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return matcher{}, fmt.Errorf("bad regexp %q: %v", expr, err)
+		}
+		return matcher{re: re}, nil
+	}
+	if strings.ContainsAny(value, "*?") {
+		var b strings.Builder
+		b.WriteString("(?is)^")
+		for _, r := range value {
+			switch r {
+			case '*':
+				b.WriteString(".*")
+			case '?':
+				b.WriteString(".")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		b.WriteString("$")
+		return matcher{re: regexp.MustCompile(b.String())}, nil
+	}
+	return matcher{exact: value}, nil
+}
+
+func (m matcher) match(s string) bool {
+	if m.re != nil {
+		return m.re.MatchString(s)
+	}
+	return strings.EqualFold(m.exact, s)
+}
@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Window is what rules get to look at
+type Window struct {
+	Process string
+	Class   string
+	Title   string
+
+	Style, ExStyle uint32
+	W, H           int
+}
+
+// Rule is a set of conditions that must all hold and what to do with a
+// window that meets them
+type Rule struct {
+	conds []cond
+	// Text is the rule as written, for error messages and glo query
+	Text string
+
+	// Ignore keeps glo's hands off the window entirely
+	Ignore bool
+	// Float manages the window but leaves it out of the layout
+	Float bool
+	// Tile does nothing by itself, it's there to stop a later rule, e.g. a
+	// default one, from floating or ignoring the window
+	Tile bool
+
+	// Workspace and Monitor (1-based, left to right) pick where the window
+	// opens instead of the active workspace of the monitor it's on
+	Workspace int
+	Monitor   int
+	// Master puts the window in the master slot instead of the end of the stack
+	Master bool
+	// Width and Height fix the window's size, it's centered in its tile
+	// or on its monitor when floating
+	Width, Height int
+}
+
+func (r Rule) Matches(w Window) bool {
+	for _, c := range r.conds {
+		if !c.matches(w) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rule) String() string {
+	return r.Text
+}
+
+// Set is an ordered list of rules, the first one that matches wins
+type Set []Rule
+
+func (s Set) Match(w Window) (Rule, bool) {
+	for _, r := range s {
+		if r.Matches(w) {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Parse reads a rule from its fields, conditions first and then actions:
+//
+//	process=steam.exe title="Friends List" float size 400x800
+//
+// conditions are process, class and title (= or !=, see match), style and
+// exstyle (= all flags set, != none of them set, flags joined with |) and
+// width and height (<, <=, =, >=, >). actions are ignore, float, tile,
+// workspace N, monitor N, master and size WxH
+func Parse(fields []string) (Rule, error) {
+	r := Rule{Text: strings.Join(quote(fields), " ")}
+
+	i := 0
+	for ; i < len(fields); i++ {
+		c, ok, err := parseCond(fields[i])
+		if err != nil {
+			return Rule{}, err
+		}
+		if !ok {
+			break
+		}
+		r.conds = append(r.conds, c)
+	}
+	if len(r.conds) == 0 {
+		return Rule{}, fmt.Errorf("rule needs at least one condition (process=, class=, title=, style=, exstyle=, width<, height<...)")
+	}
+
+	actions := fields[i:]
+	if len(actions) == 0 {
+		return Rule{}, fmt.Errorf("rule needs an action (ignore, float, tile, workspace N, monitor N, master, size WxH)")
+	}
+	for len(actions) > 0 {
+		n, err := r.action(actions)
+		if err != nil {
+			return Rule{}, err
+		}
+		actions = actions[n:]
+	}
+
+	switch {
+	case r.Ignore && (r.Float || r.Tile || r.Workspace != 0 || r.Monitor != 0 || r.Master || r.Width != 0):
+		return Rule{}, fmt.Errorf("ignore can't be combined with other actions")
+	case r.Float && r.Tile:
+		return Rule{}, fmt.Errorf("float and tile contradict each other")
+	case r.Float && r.Master:
+		return Rule{}, fmt.Errorf("a floating window can't be master")
+	}
+	return r, nil
+}
+
+// action reads one action off the front of args, returning how many fields
+// it used
+func (r *Rule) action(args []string) (int, error) {
+	switch args[0] {
+	case "ignore":
+		r.Ignore = true
+		return 1, nil
+	case "float":
+		r.Float = true
+		return 1, nil
+	case "tile":
+		r.Tile = true
+		return 1, nil
+	case "master":
+		r.Master = true
+		return 1, nil
+
+	case "workspace", "monitor":
+		if len(args) < 2 {
+			return 0, fmt.Errorf("%s wants a number", args[0])
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("%s wants a number from 1, got %q", args[0], args[1])
+		}
+		if args[0] == "workspace" {
+			r.Workspace = n
+		} else {
+			r.Monitor = n
+		}
+		return 2, nil
+
+	case "size":
+		if len(args) < 2 {
+			return 0, fmt.Errorf("size wants WIDTHxHEIGHT")
+		}
+		ws, hs, ok := strings.Cut(args[1], "x")
+		w, errW := strconv.Atoi(ws)
+		h, errH := strconv.Atoi(hs)
+		if !ok || errW != nil || errH != nil || w <= 0 || h <= 0 {
+			return 0, fmt.Errorf("size wants WIDTHxHEIGHT, got %q", args[1])
+		}
+		r.Width, r.Height = w, h
+		return 2, nil
+	}
+
+	if _, ok, _ := parseCond(args[0]); ok {
+		return 0, fmt.Errorf("condition %q after an action, conditions go first", args[0])
+	}
+	return 0, fmt.Errorf("unknown rule action %q", args[0])
+}
+
+// quote puts back the quotes the config tokenizer took off
+func quote(fields []string) []string {
+	out := make([]string, len(fields))
+	for i, f := range fields {
+		if k, v, ok := strings.Cut(f, "="); ok && strings.ContainsAny(v, " \t#") {
+			f = k + `="` + v + `"`
+		}
+		out[i] = f
+	}
+	return out
+}
@@ -0,0 +1,158 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, line string) Rule {
+	t.Helper()
+	r, err := Parse(strings.Fields(line))
+	if err != nil {
+		t.Fatalf("%s: %v", line, err)
+	}
+	return r
+}
+
+const (
+	wsPopup     = 0x80000000
+	wsCaption   = 0x00C00000
+	wsExTool    = 0x00000080
+	wsExTopmost = 0x00000008
+)
+
+func TestMatches(t *testing.T) {
+	steam := Window{Process: "steam.exe", Class: "SDL_app", Title: "Friends List", W: 400, H: 800}
+	popup := Window{Process: "app.exe", Title: "", Style: wsPopup, ExStyle: wsExTool | wsExTopmost, W: 200, H: 50}
+
+	for _, tc := range []struct {
+		rule string
+		w    Window
+		want bool
+	}{
+		{"process=steam.exe float", steam, true},
+		{"process=STEAM.EXE float", steam, true},
+		{"process=steam float", steam, false},
+		{"process!=steam.exe float", steam, false},
+		{"process!=code.exe float", steam, true},
+
+		{"title=Friends* float", steam, true},
+		{"title=*list float", steam, true},
+		{"title=Friends?List float", steam, true},
+		{"title=Friends? float", steam, false},
+		{"title=*.exe float", Window{Title: "setup.exe"}, true},
+		{"title=*.exe float", Window{Title: "setupxexe"}, false},
+
+		{"title=re:^friends float", steam, true},
+		{"title=re:list$ float", steam, true},
+		{"title=re:^list float", steam, false},
+		{"title!=re:^friends float", steam, false},
+		{"class=re:^sdl_(app|game)$ float", steam, true},
+
+		{"style=WS_POPUP float", popup, true},
+		{"style=ws_popup float", popup, true},
+		{"style=WS_POPUP|WS_CAPTION float", popup, false},
+		{"style=WS_POPUP|WS_CAPTION float", Window{Style: wsPopup | wsCaption}, true},
+		{"style!=WS_CAPTION float", popup, true},
+		{"style!=WS_POPUP|WS_CAPTION float", popup, false},
+		{"style=0x80000000 float", popup, true},
+		{"exstyle=WS_EX_TOOLWINDOW|WS_EX_TOPMOST float", popup, true},
+		{"exstyle!=WS_EX_APPWINDOW float", popup, true},
+
+		{"width<400 float", steam, false},
+		{"width<=400 float", steam, true},
+		{"width=400 float", steam, true},
+		{"width>=401 float", steam, false},
+		{"height>799 float", steam, true},
+		{"height<100 float", popup, true},
+
+		// every condition has to hold
+		{"process=app.exe height<100 float", popup, true},
+		{"process=app.exe height>100 float", popup, false},
+	} {
+		if got := parse(t, tc.rule).Matches(tc.w); got != tc.want {
+			t.Errorf("%q matching %+v = %v, want %v", tc.rule, tc.w, got, tc.want)
+		}
+	}
+}
+
+func TestActions(t *testing.T) {
+	r := parse(t, "process=spotify.exe workspace 9 monitor 2 master size 800x600")
+	if r.Workspace != 9 || r.Monitor != 2 || !r.Master || r.Width != 800 || r.Height != 600 {
+		t.Fatalf("got %+v", r)
+	}
+	if r := parse(t, "class=x ignore"); !r.Ignore {
+		t.Fatalf("got %+v", r)
+	}
+	if r := parse(t, "class=x tile"); !r.Tile || r.Float {
+		t.Fatalf("got %+v", r)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		rule string
+		want string
+	}{
+		{"float", "rule needs at least one condition"},
+		{"process=a.exe", "rule needs an action"},
+		{"colour=red float", `unknown rule condition "colour"`},
+		{"process<a float", `process takes = or !=, got "<"`},
+		{"style>WS_POPUP float", `style takes = or !=, got ">"`},
+		{"style=WS_NOPE float", `unknown style "WS_NOPE"`},
+		{"exstyle=WS_POPUP float", `unknown style "WS_POPUP"`},
+		{"width!=3 float", "width takes <, <=, =, >= or >"},
+		{"height<tall float", `height wants a number of pixels, got "tall"`},
+		{"title=re:( float", `title: bad regexp "("`},
+		{"class=x fly", `unknown rule action "fly"`},
+		{"class=x float title=y", `condition "title=y" after an action, conditions go first`},
+		{"class=x workspace", "workspace wants a number"},
+		{"class=x monitor 0", `monitor wants a number from 1, got "0"`},
+		{"class=x size 800", `size wants WIDTHxHEIGHT, got "800"`},
+		{"class=x ignore float", "ignore can't be combined with other actions"},
+		{"class=x float tile", "float and tile contradict each other"},
+		{"class=x float master", "a floating window can't be master"},
+	} {
+		_, err := Parse(strings.Fields(tc.rule))
+		if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+			t.Errorf("%q: got %v, want %q", tc.rule, err, tc.want)
+		}
+	}
+}
+
+func TestFirstMatchWins(t *testing.T) {
+	// user rules are put in front of the defaults, so a tile rule beats a
+	// default float for the same window
+	set := Set{
+		parse(t, "process=explorer.exe class=Dialog tile"),
+		parse(t, "class=Dialog float"),
+		parse(t, "process=explorer.exe ignore"),
+	}
+	for _, tc := range []struct {
+		w    Window
+		want string
+	}{
+		{Window{Process: "explorer.exe", Class: "Dialog"}, "process=explorer.exe class=Dialog tile"},
+		{Window{Process: "notepad.exe", Class: "Dialog"}, "class=Dialog float"},
+		{Window{Process: "explorer.exe", Class: "CabinetWClass"}, "process=explorer.exe ignore"},
+	} {
+		r, ok := set.Match(tc.w)
+		if !ok || r.Text != tc.want {
+			t.Errorf("%+v matched %q, want %q", tc.w, r.Text, tc.want)
+		}
+	}
+	if r, ok := set.Match(Window{Process: "code.exe"}); ok {
+		t.Errorf("code.exe matched %q", r.Text)
+	}
+}
+
+func TestText(t *testing.T) {
+	// the config tokenizer takes the quotes off, Text puts them back
+	r, err := Parse([]string{"process=steam.exe", "title=Friends List", "float"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `process=steam.exe title="Friends List" float`; r.String() != want {
+		t.Fatalf("got %q, want %q", r, want)
+	}
+}
@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// the window styles worth writing rules about, from winuser.h
+var styles = map[string]uint32{
+	"WS_POPUP":       0x80000000,
+	"WS_CHILD":       0x40000000,
+	"WS_MINIMIZE":    0x20000000,
+	"WS_VISIBLE":     0x10000000,
+	"WS_DISABLED":    0x08000000,
+	"WS_MAXIMIZE":    0x01000000,
+	"WS_CAPTION":     0x00C00000,
+	"WS_BORDER":      0x00800000,
+	"WS_DLGFRAME":    0x00400000,
+	"WS_VSCROLL":     0x00200000,
+	"WS_HSCROLL":     0x00100000,
+	"WS_SYSMENU":     0x00080000,
+	"WS_THICKFRAME":  0x00040000,
+	"WS_MINIMIZEBOX": 0x00020000,
+	"WS_MAXIMIZEBOX": 0x00010000,
+}
+
+var exStyles = map[string]uint32{
+	"WS_EX_DLGMODALFRAME": 0x00000001,
+	"WS_EX_TOPMOST":       0x00000008,
+	"WS_EX_TRANSPARENT":   0x00000020,
+	"WS_EX_TOOLWINDOW":    0x00000080,
+	"WS_EX_WINDOWEDGE":    0x00000100,
+	"WS_EX_CLIENTEDGE":    0x00000200,
+	"WS_EX_CONTEXTHELP":   0x00000400,
+	"WS_EX_APPWINDOW":     0x00040000,
+	"WS_EX_LAYERED":       0x00080000,
+	"WS_EX_NOACTIVATE":    0x08000000,
+}
+
+// parseFlags reads flags joined with |, each a name from the tables above
+// or a hex number
+func parseFlags(value string, ex bool) (uint32, error) {
+	table := styles
+	if ex {
+		table = exStyles
+	}
+
+	var bits uint32
+	for _, name := range strings.Split(value, "|") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if bit, ok := table[name]; ok {
+			bits |= bit
+			continue
+		}
+		if hex, ok := strings.CutPrefix(name, "0X"); ok {
+			n, err := strconv.ParseUint(hex, 16, 32)
+			if err == nil {
+				bits |= uint32(n)
+				continue
+			}
+		}
+		return 0, fmt.Errorf("unknown style %q", name)
+	}
+	if bits == 0 {
+		return 0, fmt.Errorf("style %q has no bits set", value)
+	}
+	return bits, nil
+}
@@ -2,22 +2,18 @@ package window
 
 import (
 	"fmt"
-	"syscall"
-	"time"
-	"unsafe"
-)
-
-var user32 = syscall.NewLazyDLL("user32.dll")
-var (
-	procIsIconic            = user32.NewProc("IsIconic")
-	procGetWindowRectCached = user32.NewProc("GetWindowRect")
-	procMoveWindow          = user32.NewProc("MoveWindow")
-	procShowWindow          = user32.NewProc("ShowWindow")
+	"glo/platform"
 )
 
 type Window struct {
+	b    platform.Backend
 	hwnd uintptr
 
+	// fixed for the life of the window so they're read once
+	Class       string
+	ProcessName string
+	PID         uint32
+
 	width  int
 	height int
 
@@ -27,52 +23,43 @@ type Window struct {
 	Meta struct {
 		Ox, Oy, Ow, Oh int // original position and size
 	}
-
-	// event handling
-	onMinimize []func()
-	onRestore  []func()
-	onClose    []func()
-	lastIconic bool
-	monitoring bool
 }
 
-func New(hwnd uintptr) *Window {
-	w := &Window{hwnd: hwnd}
+// New fails if hwnd's rect can't be read, usually because it just closed
+func New(b platform.Backend, hwnd uintptr) (*Window, error) {
+	w := &Window{b: b, hwnd: hwnd}
 
 	if err := w.updateRect(); err != nil {
-		panic(fmt.Errorf("failed to create window: %v", err))
+		return nil, fmt.Errorf("failed to create window: %v", err)
 	}
 
 	w.Meta.Ox, w.Meta.Oy, w.Meta.Ow, w.Meta.Oh = w.x, w.y, w.width, w.height
 
-	// capture initial iconic state
-	iconic := isIconic(hwnd)
-	w.lastIconic = iconic
-	return w
+	info := b.Info(hwnd)
+	w.Class, w.ProcessName, w.PID = info.Class, info.Process, info.PID
+
+	return w, nil
 }
 
 func (w *Window) Hwnd() uintptr {
 	return w.hwnd
 }
 
-func (w *Window) updateRect() error {
-
-	var rect struct {
-		Left   int32
-		Top    int32
-		Right  int32
-		Bottom int32
-	}
+// Title is read fresh each time, apps change it as they go
+func (w *Window) Title() string {
+	return w.b.Info(w.hwnd).Title
+}
 
-	r, _, _ := procGetWindowRectCached.Call(w.hwnd, uintptr(unsafe.Pointer(&rect)))
-	if r == 0 {
-		return fmt.Errorf("GetWindowRect failed")
+func (w *Window) updateRect() error {
+	x, y, width, height, err := w.b.GetRect(w.hwnd)
+	if err != nil {
+		return err
 	}
 
-	w.x = int(rect.Left)
-	w.y = int(rect.Top)
-	w.width = int(rect.Right - rect.Left)
-	w.height = int(rect.Bottom - rect.Top)
+	w.x = x
+	w.y = y
+	w.width = width
+	w.height = height
 
 	return nil
 }
@@ -101,9 +88,8 @@ func (w *Window) MoveTo(x, y int) error {
 		return fmt.Errorf("failed to get window position: %v", err)
 	}
 
-	r, _, _ := procMoveWindow.Call(w.hwnd, uintptr(x), uintptr(y), uintptr(w.width), uintptr(w.height), 1)
-	if r == 0 {
-		return fmt.Errorf("MoveWindow failed")
+	if err := w.b.SetRect(w.hwnd, x, y, w.width, w.height); err != nil {
+		return err
 	}
 
 	w.x = x
@@ -118,9 +104,8 @@ func (w *Window) MoveDelta(dx, dy int) error {
 		return fmt.Errorf("failed to get window position: %v", err)
 	}
 
-	r, _, _ := procMoveWindow.Call(w.hwnd, uintptr(w.x+dx), uintptr(w.y+dy), uintptr(w.width), uintptr(w.height), 1)
-	if r == 0 {
-		return fmt.Errorf("MoveWindow failed")
+	if err := w.b.SetRect(w.hwnd, w.x+dx, w.y+dy, w.width, w.height); err != nil {
+		return err
 	}
 
 	w.x += dx
@@ -130,7 +115,7 @@ func (w *Window) MoveDelta(dx, dy int) error {
 }
 
 func (w *Window) IsMinimized() bool {
-	return isIconic(w.hwnd)
+	return w.b.IsMinimized(w.hwnd)
 }
 
 func (w *Window) Resize(width, height int) error {
@@ -139,9 +124,8 @@ func (w *Window) Resize(width, height int) error {
 		return fmt.Errorf("failed to get window position: %v", err)
 	}
 
-	r, _, _ := procMoveWindow.Call(w.hwnd, uintptr(w.x), uintptr(w.y), uintptr(width), uintptr(height), 1)
-	if r == 0 {
-		return fmt.Errorf("MoveWindow failed")
+	if err := w.b.SetRect(w.hwnd, w.x, w.y, width, height); err != nil {
+		return err
 	}
 
 	w.width = width
@@ -156,9 +140,8 @@ func (w *Window) ResizeDelta(dw, dh int) error {
 		return fmt.Errorf("failed to get window position: %v", err)
 	}
 
-	r, _, _ := procMoveWindow.Call(w.hwnd, uintptr(w.x), uintptr(w.y), uintptr(w.width+dw), uintptr(w.height+dh), 1)
-	if r == 0 {
-		return fmt.Errorf("MoveWindow failed")
+	if err := w.b.SetRect(w.hwnd, w.x, w.y, w.width+dw, w.height+dh); err != nil {
+		return err
 	}
 
 	w.width += dw
@@ -182,9 +165,8 @@ func (w *Window) SetRect(x, y, width, height int) error {
 		return fmt.Errorf("failed to get window position: %v", err)
 	}
 
-	r, _, _ := procMoveWindow.Call(w.hwnd, uintptr(x), uintptr(y), uintptr(width), uintptr(height), 1)
-	if r == 0 {
-		return fmt.Errorf("MoveWindow failed")
+	if err := w.b.SetRect(w.hwnd, x, y, width, height); err != nil {
+		return err
 	}
 
 	w.x = x
@@ -197,83 +179,12 @@ func (w *Window) SetRect(x, y, width, height int) error {
 
 func (w *Window) showWindow(nCmdShow int) error {
 	// No need to refresh rect just to change show state
-	r, _, _ := procShowWindow.Call(w.hwnd, uintptr(nCmdShow))
-	if r == 0 {
-		return fmt.Errorf("ShowWindow failed")
-	}
-
-	return nil
+	return w.b.ShowWindow(w.hwnd, nCmdShow)
 }
 
-func (w *Window) Minimise() error { return w.showWindow(2) }
-func (w *Window) Maximise() error { return w.showWindow(3) }
-func (w *Window) Restore() error  { return w.showWindow(1) }
+func (w *Window) Minimise() error { return w.showWindow(platform.SW_SHOWMINIMIZED) }
+func (w *Window) Maximise() error { return w.showWindow(platform.SW_SHOWMAXIMIZED) }
 
-func (w *Window) OnMinimize(f func()) {
-	w.onMinimize = append(w.onMinimize, f)
-	w.startMonitor()
-}
-
-func (w *Window) OnRestore(f func()) {
-	w.onRestore = append(w.onRestore, f)
-	w.startMonitor()
-}
-
-func (w *Window) OnClose(f func()) {
-	w.onClose = append(w.onClose, f)
-	w.startMonitor()
-}
-
-func (w *Window) startMonitor() {
-	if w.monitoring {
-		return
-	}
-	w.monitoring = true
-	go w.monitorLoop()
-}
-
-func (w *Window) monitorLoop() {
-	isWin := user32.NewProc("IsWindow")
-	for {
-		r, _, _ := isWin.Call(w.hwnd)
-		if r == 0 {
-			for _, cb := range w.onClose {
-				cb()
-			}
-			return
-		}
-		iconic := isIconic(w.hwnd)
-		if iconic != w.lastIconic {
-			if iconic {
-				for _, cb := range w.onMinimize {
-					cb()
-				}
-			} else {
-				for _, cb := range w.onRestore {
-					cb()
-				}
-			}
-			w.lastIconic = iconic
-		}
-		time.Sleep(150 * time.Millisecond)
-	}
-}
-
-func UsableScreenDimensions() (int, int) {
-	var rect struct {
-		Left, Top, Right, Bottom int32
-	}
-
-	user32 := syscall.NewLazyDLL("user32.dll")
-	spi := user32.NewProc("SystemParametersInfoW")
-	SPI_GETWORKAREA := uintptr(0x0030)
-	r := uintptr(unsafe.Pointer(&rect))
-
-	spi.Call(SPI_GETWORKAREA, 0, r, 0)
-	return int(rect.Right), int(rect.Bottom)
-}
-
-func isIconic(hwnd uintptr) bool {
-	r, _, _ := procIsIconic.Call(hwnd)
-	return r != 0
-}
+// Restore un-minimizes/un-maximizes without activating, tiling calls it on
+// every window and focus shouldn't jump to whichever was restored last
+func (w *Window) Restore() error { return w.showWindow(platform.SW_SHOWNOACTIVATE) }
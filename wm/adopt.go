@@ -0,0 +1,122 @@
+package wm
+
+import (
+	"fmt"
+	"glo/rules"
+	"glo/window"
+	"sort"
+)
+
+// AdoptOrder decides the layout order of windows that already existed when
+// glo started or tiling was turned on
+type AdoptOrder string
+
+const (
+	AdoptZOrder   AdoptOrder = "zorder"   // topmost window becomes master
+	AdoptProcess  AdoptOrder = "process"  // grouped by executable name, then pid
+	AdoptPosition AdoptOrder = "position" // left to right, then top to bottom
+)
+
+func ParseAdoptOrder(s string) (AdoptOrder, error) {
+	switch o := AdoptOrder(s); o {
+	case AdoptZOrder, AdoptProcess, AdoptPosition:
+		return o, nil
+	case "":
+		return AdoptZOrder, nil
+	}
+	return "", fmt.Errorf("unknown adopt order %q (want zorder, process or position)", s)
+}
+
+// Adopt starts managing every existing app window glo doesn't know about yet
+// and reports how many it picked up
+func (m *WindowManager) Adopt() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := m.adopt()
+	if n > 0 && m.tiling {
+		m.tile()
+	}
+	return n
+}
+
+// adopt is Adopt without the retile, m.mu must be held
+func (m *WindowManager) adopt() int {
+	var found []*window.Window
+	matched := make(map[uintptr]rules.Rule)
+	for _, hwnd := range m.b.Windows() {
+		if m.windows[hwnd] != nil || m.ignored[hwnd] || !m.b.IsAppWindow(hwnd) {
+			continue
+		}
+		rule, _ := m.ruleFor(hwnd)
+		if rule.Ignore {
+			m.ignored[hwnd] = true
+			continue
+		}
+		w, err := window.New(m.b, hwnd)
+		if err != nil {
+			fmt.Printf("[wm] not adopting %#x: %v\n", hwnd, err)
+			continue
+		}
+		matched[hwnd] = rule
+		found = append(found, w)
+	}
+
+	found = m.sortAdopted(found)
+
+	for _, w := range found {
+		m.windows[w.Hwnd()] = w
+		m.emit(Event{Type: EventWindowManaged, Hwnd: w.Hwnd()})
+		if w.IsMinimized() {
+			m.minimized[w.Hwnd()] = true
+		}
+		m.place(w.Hwnd(), matched[w.Hwnd()])
+	}
+	return len(found)
+}
+
+// sortAdopted reorders ws, which come in z-order, by the configured order.
+// windows that close while it looks at them are dropped
+func (m *WindowManager) sortAdopted(ws []*window.Window) []*window.Window {
+	switch m.adoptOrder {
+	case AdoptProcess:
+		type key struct {
+			name string
+			pid  uint32
+		}
+		keys := make(map[uintptr]key, len(ws))
+		for _, w := range ws {
+			info := m.b.Info(w.Hwnd())
+			keys[w.Hwnd()] = key{info.Process, info.PID}
+		}
+		sort.SliceStable(ws, func(i, j int) bool {
+			a, b := keys[ws[i].Hwnd()], keys[ws[j].Hwnd()]
+			if a.name != b.name {
+				return a.name < b.name
+			}
+			return a.pid < b.pid
+		})
+	case AdoptPosition:
+		// read once up front, the comparator runs n log n times
+		type pos struct{ x, y int }
+		at := make(map[uintptr]pos, len(ws))
+		open := ws[:0]
+		for _, w := range ws {
+			x, y, _, _, err := m.b.GetRect(w.Hwnd())
+			if err != nil {
+				continue
+			}
+			at[w.Hwnd()] = pos{x, y}
+			open = append(open, w)
+		}
+		ws = open
+		sort.SliceStable(ws, func(i, j int) bool {
+			a, b := at[ws[i].Hwnd()], at[ws[j].Hwnd()]
+			if a.x != b.x {
+				return a.x < b.x
+			}
+			return a.y < b.y
+		})
+	}
+	return ws
+}
@@ -0,0 +1,87 @@
+package wm
+
+import (
+	"glo/platform/fake"
+	"glo/window"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAdoptSkipsClosedWindow(t *testing.T) {
+	for _, order := range []AdoptOrder{AdoptZOrder, AdoptProcess, AdoptPosition} {
+		t.Run(string(order), func(t *testing.T) {
+			b := &closing{fake.New(1000, 500), make(map[uintptr]bool)}
+			a := b.Open(fake.Window{Title: "a", X: 300})
+			gone := b.Open(fake.Window{Title: "gone", X: 200})
+			c := b.Open(fake.Window{Title: "c", X: 100})
+			b.gone[gone] = true
+
+			m := New(b, fake.NewClock(time.Unix(0, 0)), Options{MasterFrac: 0.5, AdoptOrder: order})
+			if n := m.Adopt(); n != 2 {
+				t.Fatalf("adopted %d windows, want 2", n)
+			}
+			got := m.Windows()
+			if len(got) != 2 || !slices.Contains(got, a) || !slices.Contains(got, c) {
+				t.Fatalf("managing %v, want %#x and %#x", got, a, c)
+			}
+		})
+	}
+}
+
+func TestAdoptOrder(t *testing.T) {
+	for _, tc := range []struct {
+		order AdoptOrder
+		want  []string
+	}{
+		// topmost first, the last opened is on top
+		{AdoptZOrder, []string{"c", "b", "a"}},
+		{AdoptProcess, []string{"b", "c", "a"}},
+		{AdoptPosition, []string{"c", "a", "b"}},
+	} {
+		t.Run(string(tc.order), func(t *testing.T) {
+			b := fake.New(1000, 500)
+			titles := make(map[uintptr]string)
+			for _, w := range []fake.Window{
+				{Title: "a", Process: "z.exe", X: 100, Y: 100},
+				{Title: "b", Process: "a.exe", PID: 2, X: 100, Y: 300},
+				{Title: "c", Process: "a.exe", PID: 3, X: 0, Y: 400},
+			} {
+				titles[b.Open(w)] = w.Title
+			}
+
+			m := New(b, fake.NewClock(time.Unix(0, 0)), Options{MasterFrac: 0.5, AdoptOrder: tc.order})
+			m.Adopt()
+			var got []string
+			for _, hwnd := range m.Windows() {
+				got = append(got, titles[hwnd])
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("adopted %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAdoptByPositionDropsClosedWindow(t *testing.T) {
+	b := &closing{fake.New(1000, 500), make(map[uintptr]bool)}
+	m := New(b, fake.NewClock(time.Unix(0, 0)), Options{MasterFrac: 0.5, AdoptOrder: AdoptPosition})
+	right := b.Open(fake.Window{Title: "right", X: 300})
+	gone := b.Open(fake.Window{Title: "gone", X: 200})
+	left := b.Open(fake.Window{Title: "left", X: 100})
+
+	var ws []*window.Window
+	for _, hwnd := range []uintptr{left, gone, right} {
+		w, err := window.New(b, hwnd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ws = append(ws, w)
+	}
+	b.gone[gone] = true
+	ws = m.sortAdopted(ws)
+
+	if len(ws) != 2 || ws[0].Hwnd() != left || ws[1].Hwnd() != right {
+		t.Fatalf("sorted to %v, want left then right", ws)
+	}
+}
@@ -0,0 +1,63 @@
+package wm
+
+import (
+	"fmt"
+	"glo/hotkey"
+)
+
+// Binding runs Command whenever Key is pressed
+type Binding struct {
+	Key     hotkey.Key
+	Command string
+}
+
+// Bind replaces every registered hotkey with bindings. a binding that can't
+// be registered, usually because another program owns the key, is skipped
+// and the rest still work. errs[i] is the problem with bindings[i], if any
+func (m *WindowManager) Bind(bindings []Binding) (errs []error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id := range m.bindings {
+		m.b.UnregisterHotkey(id)
+	}
+	m.bindings = make(map[int]string, len(bindings))
+
+	errs = make([]error, len(bindings))
+	for i, bnd := range bindings {
+		if err := bnd.Key.Validate(); err != nil {
+			errs[i] = err
+			continue
+		}
+		if err := ValidateCommand(bnd.Command); err != nil {
+			errs[i] = err
+			continue
+		}
+		id := i + 1
+		if err := m.b.RegisterHotkey(id, bnd.Key.Modifiers, bnd.Key.VK); err != nil {
+			errs[i] = fmt.Errorf("can't register hotkey: %v", err)
+			continue
+		}
+		m.bindings[id] = bnd.Command
+	}
+	return errs
+}
+
+func (m *WindowManager) handleHotkey(id int) {
+	m.mu.Lock()
+	cmd, ok := m.bindings[id]
+	m.mu.Unlock()
+
+	if ok {
+		m.Exec(cmd)
+	}
+}
+
+// Quit asks whoever is waiting on Done to shut glo down
+func (m *WindowManager) Quit() {
+	m.quitOnce.Do(func() { close(m.done) })
+}
+
+func (m *WindowManager) Done() <-chan struct{} {
+	return m.done
+}
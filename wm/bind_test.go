@@ -0,0 +1,126 @@
+package wm
+
+import (
+	"glo/hotkey"
+	"glo/layout"
+	"glo/platform/fake"
+	"testing"
+	"time"
+)
+
+func key(t *testing.T, s string) hotkey.Key {
+	t.Helper()
+	k, err := hotkey.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// press fires hotkey id on the backend and hands it to the manager the way
+// Run would
+func (h *harness) press(id int) {
+	h.t.Helper()
+	if !h.b.Press(id) {
+		h.t.Fatalf("hotkey %d isn't registered", id)
+	}
+	h.m.handleHotkey(<-h.b.Hotkeys())
+	h.settle()
+}
+
+func TestHotkeys(t *testing.T) {
+	h := newHarness(t, Options{})
+	errs := h.m.Bind([]Binding{
+		{key(t, "win+shift+o"), "toggle"},
+		{key(t, "win+l"), "grow-master"},
+	})
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if mods, vk, ok := h.b.Hotkey(1); !ok || mods != hotkey.MOD_WIN|hotkey.MOD_SHIFT || vk != 'O' {
+		t.Fatalf("hotkey 1 is %#x %#x %v", mods, vk, ok)
+	}
+
+	a, b := h.open("a"), h.open("b")
+	h.press(1)
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+
+	h.press(2)
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 550, H: 500})
+	h.wantRect(b, layout.Rect{X: 550, Y: 0, W: 450, H: 500})
+
+	// toggling off puts the windows back the way they were found
+	h.press(1)
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 300, H: 200})
+	h.wantRect(b, layout.Rect{X: 0, Y: 0, W: 300, H: 200})
+}
+
+func TestBindSkipsBadBindings(t *testing.T) {
+	h := newHarness(t, Options{})
+	errs := h.m.Bind([]Binding{
+		{key(t, "win+t"), "toggle"},
+		{key(t, "win+t"), "rotate"},
+		{key(t, "win+r"), "spin"},
+		{key(t, "win+y"), "rotate"},
+	})
+	if errs[0] != nil || errs[3] != nil {
+		t.Fatalf("good bindings failed: %v", errs)
+	}
+	if errs[1] == nil {
+		t.Error("a key bound twice was registered twice")
+	}
+	if errs[2] == nil {
+		t.Error("an unknown command was bound")
+	}
+	if _, _, ok := h.b.Hotkey(3); ok {
+		t.Error("the unknown command's key was registered")
+	}
+
+	// binding again drops the old keys
+	h.m.Bind([]Binding{{key(t, "win+u"), "toggle"}})
+	if _, _, ok := h.b.Hotkey(4); ok {
+		t.Error("win+y is still registered after rebinding")
+	}
+	h.press(1)
+	if !h.m.Tiling() {
+		t.Error("the new binding didn't run")
+	}
+}
+
+func TestRunStopsWithBackend(t *testing.T) {
+	b := fake.New(1000, 500)
+	m := New(b, fake.NewClock(time.Unix(0, 0)), Options{MasterFrac: 0.5})
+	done := make(chan struct{})
+	go func() {
+		m.Run(nil)
+		close(done)
+	}()
+	b.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run kept going after the backend closed")
+	}
+}
+
+func TestShutdownRestoresHiddenWindows(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a := h.open("a")
+	h.exec("send-to-workspace 2")
+	if w, _ := h.b.Window(a); !w.Hidden {
+		t.Fatal("a window sent to another workspace is still showing")
+	}
+
+	h.m.Shutdown()
+	w, _ := h.b.Window(a)
+	if w.Hidden {
+		t.Error("shutdown left a hidden")
+	}
+	if r := (layout.Rect{X: w.X, Y: w.Y, W: w.W, H: w.H}); r != (layout.Rect{X: 0, Y: 0, W: 300, H: 200}) {
+		t.Errorf("shutdown left a at %v", r)
+	}
+}
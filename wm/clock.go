@@ -0,0 +1,21 @@
+package wm
+
+import "time"
+
+// Clock is the manager's only source of time so the retile debounce can be
+// driven by hand in tests
+type Clock interface {
+	Now() time.Time
+	// AfterFunc runs f once d has passed, stop cancels it if it hasn't run yet
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+type systemClock struct{}
+
+func SystemClock() Clock { return systemClock{} }
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
@@ -0,0 +1,285 @@
+package wm
+
+import (
+	"fmt"
+	"glo/layout"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// command is one thing a hotkey (or anything else driving glo) can ask for.
+// check validates the arguments without touching any state so configs can be
+// checked before they are applied
+type command struct {
+	usage string
+	check func(args []string) error
+	run   func(m *WindowManager, args []string)
+}
+
+var commands = map[string]command{
+	"toggle":        {"toggle", noArgs, func(m *WindowManager, _ []string) { m.Toggle() }},
+	"grow-master":   {"grow-master", noArgs, func(m *WindowManager, _ []string) { m.GrowMaster() }},
+	"shrink-master": {"shrink-master", noArgs, func(m *WindowManager, _ []string) { m.ShrinkMaster() }},
+	"inc-master":    {"inc-master", noArgs, func(m *WindowManager, _ []string) { m.IncMaster() }},
+	"dec-master":    {"dec-master", noArgs, func(m *WindowManager, _ []string) { m.DecMaster() }},
+	"grow-split":    {"grow-split", noArgs, func(m *WindowManager, _ []string) { m.GrowSplit() }},
+	"shrink-split":  {"shrink-split", noArgs, func(m *WindowManager, _ []string) { m.ShrinkSplit() }},
+	"grow-window":   {"grow-window", noArgs, func(m *WindowManager, _ []string) { m.GrowWindow() }},
+	"shrink-window": {"shrink-window", noArgs, func(m *WindowManager, _ []string) { m.ShrinkWindow() }},
+	"equalize":      {"equalize", noArgs, func(m *WindowManager, _ []string) { m.Equalize() }},
+	"rotate":        {"rotate", noArgs, func(m *WindowManager, _ []string) { m.Rotate() }},
+	"toggle-float":  {"toggle-float", noArgs, func(m *WindowManager, _ []string) { m.ToggleFloat() }},
+	"quit":          {"quit", noArgs, func(m *WindowManager, _ []string) { m.Quit() }},
+
+	"move-to-monitor": {"move-to-monitor next|prev", checkDirection, func(m *WindowManager, args []string) {
+		m.MoveToMonitor(direction(args[0]))
+	}},
+	"focus-monitor": {"focus-monitor next|prev", checkDirection, func(m *WindowManager, args []string) {
+		m.FocusMonitor(direction(args[0]))
+	}},
+	"focus": {"focus left|right|up|down|next|prev", checkMotion, func(m *WindowManager, args []string) {
+		if d, err := layout.ParseDirection(args[0]); err == nil {
+			m.FocusDirection(d)
+		} else {
+			m.FocusStep(direction(args[0]))
+		}
+	}},
+	"swap": {"swap left|right|up|down|next|prev", checkMotion, func(m *WindowManager, args []string) {
+		if d, err := layout.ParseDirection(args[0]); err == nil {
+			m.SwapDirection(d)
+		} else {
+			m.SwapStep(direction(args[0]))
+		}
+	}},
+	"promote": {"promote", noArgs, func(m *WindowManager, _ []string) { m.Promote() }},
+	"layout": {"layout NAME|next|prev", checkLayout, func(m *WindowManager, args []string) {
+		if checkDirection(args) == nil {
+			m.CycleLayout(direction(args[0]))
+			return
+		}
+		m.SetLayout(args[0])
+	}},
+	"orientation": {"orientation left|right|top|bottom|next|prev|mirror", checkOrientation, func(m *WindowManager, args []string) {
+		switch args[0] {
+		case "next", "prev":
+			m.CycleOrientation(direction(args[0]))
+		case "mirror":
+			m.MirrorOrientation()
+		default:
+			o, _ := layout.ParseOrientation(args[0])
+			m.SetOrientation(o)
+		}
+	}},
+	"column-width": {"column-width 1/3|1/2|2/3|FRACTION|next|prev", checkColumnWidth, func(m *WindowManager, args []string) {
+		if checkDirection(args) == nil {
+			m.CycleColumnWidth(direction(args[0]))
+			return
+		}
+		frac, _ := layout.ParseWidth(args[0])
+		m.SetColumnWidth(frac)
+	}},
+	"consume": {"consume", noArgs, func(m *WindowManager, _ []string) { m.Consume() }},
+	"expel":   {"expel", noArgs, func(m *WindowManager, _ []string) { m.Expel() }},
+	"split": {"split horizontal|vertical", checkSplit, func(m *WindowManager, args []string) {
+		s, _ := layout.ParseSplit(args[0])
+		m.Split(s)
+	}},
+	"container": {"container horizontal|vertical|tabbed|stacked|toggle", checkContainer, func(m *WindowManager, args []string) {
+		if args[0] == "toggle" {
+			m.ToggleContainer()
+			return
+		}
+		s, _ := layout.ParseSplit(args[0])
+		m.SetContainer(s)
+	}},
+	"move": {"move left|right|up|down", checkScreenDirection, func(m *WindowManager, args []string) {
+		d, _ := layout.ParseDirection(args[0])
+		m.Move(d)
+	}},
+	"gaps": {"gaps inc|dec|reset [inner|outer|top|right|bottom|left]", checkGaps, func(m *WindowManager, args []string) {
+		var part string
+		if len(args) == 2 {
+			part = args[1]
+		}
+		switch args[0] {
+		case "inc":
+			m.IncGaps(part)
+		case "dec":
+			m.DecGaps(part)
+		default:
+			m.ResetGaps(part)
+		}
+	}},
+	"toggle-fullscreen": {"toggle-fullscreen", noArgs, func(m *WindowManager, _ []string) { m.ToggleFullscreen() }},
+	"workspace": {"workspace 1-9", checkWorkspace, func(m *WindowManager, args []string) {
+		id, _ := strconv.Atoi(args[0])
+		m.SwitchWorkspace(id)
+	}},
+	"send-to-workspace": {"send-to-workspace 1-9", checkWorkspace, func(m *WindowManager, args []string) {
+		id, _ := strconv.Atoi(args[0])
+		m.SendToWorkspace(id)
+	}},
+}
+
+// Exec runs a command line such as "workspace 3"
+func (m *WindowManager) Exec(line string) error {
+	cmd, args, err := parseCommand(line)
+	if err != nil {
+		return err
+	}
+	cmd.run(m, args)
+	return nil
+}
+
+// ValidateCommand reports whether line would be accepted by Exec
+func ValidateCommand(line string) error {
+	_, _, err := parseCommand(line)
+	return err
+}
+
+// Commands lists the usage of every command
+func Commands() []string {
+	out := make([]string, 0, len(commands))
+	for _, c := range commands {
+		out = append(out, c.usage)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func parseCommand(line string) (command, []string, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return command{}, nil, fmt.Errorf("empty command")
+	}
+
+	cmd, ok := commands[fields[0]]
+	if !ok {
+		return command{}, nil, fmt.Errorf("unknown command %q", fields[0])
+	}
+	if err := cmd.check(fields[1:]); err != nil {
+		return command{}, nil, fmt.Errorf("%s: %v (usage: %s)", fields[0], err, cmd.usage)
+	}
+	return cmd, fields[1:], nil
+}
+
+func noArgs(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("takes no arguments")
+	}
+	return nil
+}
+
+func checkDirection(args []string) error {
+	if len(args) != 1 || (args[0] != "next" && args[0] != "prev") {
+		return fmt.Errorf("want next or prev")
+	}
+	return nil
+}
+
+// checkMotion takes a screen direction or next/prev in layout order
+func checkMotion(args []string) error {
+	if len(args) == 1 {
+		if _, err := layout.ParseDirection(args[0]); err == nil {
+			return nil
+		}
+	}
+	if checkDirection(args) != nil {
+		return fmt.Errorf("want left, right, up, down, next or prev")
+	}
+	return nil
+}
+
+func direction(arg string) int {
+	if arg == "prev" {
+		return -1
+	}
+	return 1
+}
+
+func checkLayout(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("want a layout name, next or prev")
+	}
+	if checkDirection(args) == nil {
+		return nil
+	}
+	_, err := layout.New(args[0])
+	return err
+}
+
+func checkOrientation(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("want a side, next, prev or mirror")
+	}
+	if checkDirection(args) == nil || args[0] == "mirror" {
+		return nil
+	}
+	_, err := layout.ParseOrientation(args[0])
+	return err
+}
+
+func checkColumnWidth(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("want a width, next or prev")
+	}
+	if checkDirection(args) == nil {
+		return nil
+	}
+	_, err := layout.ParseWidth(args[0])
+	return err
+}
+
+// checkScreenDirection takes left, right, up or down but not next/prev
+func checkScreenDirection(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("want left, right, up or down")
+	}
+	_, err := layout.ParseDirection(args[0])
+	return err
+}
+
+func checkSplit(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("want horizontal or vertical")
+	}
+	s, err := layout.ParseSplit(args[0])
+	if err == nil && s != layout.SplitHorizontal && s != layout.SplitVertical {
+		err = fmt.Errorf("can only split horizontal or vertical, use container for %s", s)
+	}
+	return err
+}
+
+func checkContainer(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("want a split or toggle")
+	}
+	if args[0] == "toggle" {
+		return nil
+	}
+	_, err := layout.ParseSplit(args[0])
+	return err
+}
+
+func checkGaps(args []string) error {
+	if len(args) < 1 || len(args) > 2 || (args[0] != "inc" && args[0] != "dec" && args[0] != "reset") {
+		return fmt.Errorf("want inc, dec or reset and optionally which gap")
+	}
+	if len(args) == 2 && !slices.Contains(gapParts, args[1]) {
+		return fmt.Errorf("unknown gap %q (have %v)", args[1], gapParts)
+	}
+	return nil
+}
+
+func checkWorkspace(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("want a workspace number")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id < 1 || id > WorkspaceCount {
+		return fmt.Errorf("workspace must be 1-%d", WorkspaceCount)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package wm
+
+import (
+	"fmt"
+	"glo/layout"
+)
+
+// what subscribers are told about, see Subscribe
+const (
+	EventWindowManaged      = "window_managed"
+	EventWindowUnmanaged    = "window_unmanaged"
+	EventFocusChanged       = "focus_changed"
+	EventLayoutChanged      = "layout_changed"
+	EventTilingToggled      = "tiling_toggled"
+	EventMasterResized      = "master_resized"
+	EventNMasterChanged     = "nmaster_changed"
+	EventOrientationChanged = "orientation_changed"
+	EventGapsChanged        = "gaps_changed"
+	EventWorkspaceChanged   = "workspace_changed"
+)
+
+var EventTypes = []string{
+	EventWindowManaged,
+	EventWindowUnmanaged,
+	EventFocusChanged,
+	EventLayoutChanged,
+	EventTilingToggled,
+	EventMasterResized,
+	EventNMasterChanged,
+	EventOrientationChanged,
+	EventGapsChanged,
+	EventWorkspaceChanged,
+}
+
+// subscribers that fall this far behind are dropped rather than stalling
+// the manager
+const subscriberBuffer = 64
+
+// Event is one change to the manager's state. only the fields that matter to
+// Type are set, State always holds the whole picture right after the change
+type Event struct {
+	Type        string       `json:"event"`
+	Hwnd        uintptr      `json:"hwnd,omitempty"`
+	Monitor     uintptr      `json:"monitor,omitempty"`
+	Workspace   int          `json:"workspace,omitempty"`
+	Layout      string       `json:"layout,omitempty"`
+	Tiling      *bool        `json:"tiling,omitempty"`
+	MasterFrac  float64      `json:"master_frac,omitempty"`
+	NMaster     int          `json:"nmaster,omitempty"`
+	Orientation string       `json:"orientation,omitempty"`
+	Gaps        *layout.Gaps `json:"gaps,omitempty"`
+	State       *State       `json:"state,omitempty"`
+}
+
+// State is what a status bar needs to draw itself
+type State struct {
+	Tiling  bool    `json:"tiling"`
+	Focused uintptr `json:"focused"`
+	// the current monitor and what's showing on it
+	Monitor     uintptr        `json:"monitor"`
+	Workspace   int            `json:"workspace"`
+	Layout      string         `json:"layout"`
+	MasterFrac  float64        `json:"master_frac"`
+	NMaster     int            `json:"nmaster"`
+	Orientation string         `json:"orientation"`
+	Gaps        layout.Gaps    `json:"gaps"`
+	Monitors    []MonitorState `json:"monitors"`
+}
+
+type MonitorState struct {
+	Handle    uintptr `json:"handle"`
+	Primary   bool    `json:"primary"`
+	Workspace int     `json:"workspace"`
+	// workspaces with at least one window on them
+	Occupied []int `json:"occupied"`
+}
+
+func ValidateEventType(t string) error {
+	for _, et := range EventTypes {
+		if t == et {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown event %q (have %v)", t, EventTypes)
+}
+
+// State snapshots the manager
+func (m *WindowManager) State() State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.state()
+}
+
+func (m *WindowManager) state() State {
+	cur := m.current()
+	ws := cur.active()
+	s := State{
+		Tiling:      m.tiling,
+		Focused:     m.focused,
+		Monitor:     cur.handle,
+		Workspace:   ws.ID,
+		Layout:      ws.Layout.Name(),
+		MasterFrac:  ws.MasterFrac,
+		NMaster:     ws.NMaster,
+		Orientation: ws.Orientation.String(),
+		Gaps:        ws.Gaps,
+	}
+	for _, mon := range m.monitors {
+		ms := MonitorState{Handle: mon.handle, Primary: mon.primary, Workspace: mon.active().ID, Occupied: []int{}}
+		for _, w := range mon.workspaces.All() {
+			if w.Len() > 0 {
+				ms.Occupied = append(ms.Occupied, w.ID)
+			}
+		}
+		s.Monitors = append(s.Monitors, ms)
+	}
+	return s
+}
+
+// Subscribe streams every Event until cancel is called. a subscriber that
+// stops reading has its channel closed
+func (m *WindowManager) Subscribe() (events <-chan Event, cancel func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	m.subscribers[ch] = true
+	return ch, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if m.subscribers[ch] {
+			delete(m.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// emit hands ev to every subscriber, m.mu must be held
+func (m *WindowManager) emit(ev Event) {
+	if len(m.subscribers) == 0 {
+		return
+	}
+
+	s := m.state()
+	ev.State = &s
+	for ch := range m.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			fmt.Println("[wm] dropping a subscriber that stopped reading")
+			delete(m.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// announceWorkspace emits workspace_changed if the workspace in front of the
+// user isn't the one last announced, m.mu must be held
+func (m *WindowManager) announceWorkspace() {
+	cur := m.current()
+	id := cur.active().ID
+	if cur.handle == m.announced.monitor && id == m.announced.workspace {
+		return
+	}
+	m.announced.monitor, m.announced.workspace = cur.handle, id
+	m.emit(Event{Type: EventWorkspaceChanged, Monitor: cur.handle, Workspace: id})
+}
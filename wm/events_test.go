@@ -0,0 +1,127 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/platform"
+	"glo/platform/fake"
+	"testing"
+)
+
+// drop throws away whatever the backend queued, as if the hooks missed it
+func (h *harness) drop() {
+	for len(h.b.Events()) > 0 {
+		<-h.b.Events()
+	}
+}
+
+func TestMinimizeRestore(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a, b := h.open("a"), h.open("b")
+
+	h.b.ShowWindow(a, platform.SW_SHOWMINIMIZED)
+	h.settle()
+	h.wantRect(b, layout.Rect{X: 0, Y: 0, W: 1000, H: 500})
+
+	// it comes back to the slot it left
+	h.b.ShowWindow(a, platform.SW_SHOWNORMAL)
+	h.settle()
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+	if f := h.m.State().Focused; f != a {
+		t.Errorf("focused %#x after restoring a, want %#x", f, a)
+	}
+}
+
+func TestHide(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a, b := h.open("a"), h.open("b")
+
+	// a window hidden by its app is gone as far as tiling goes
+	h.b.ShowWindow(b, platform.SW_HIDE)
+	h.settle()
+	if got := h.m.Windows(); len(got) != 1 || got[0] != a {
+		t.Fatalf("managing %v after b hid", got)
+	}
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 1000, H: 500})
+
+	// one glo hid for its workspace is still managed
+	h.b.Focus(a)
+	h.settle()
+	h.exec("send-to-workspace 2")
+	if w, _ := h.b.Window(a); !w.Hidden {
+		t.Fatal("a is showing on a workspace that isn't")
+	}
+	if got := h.m.Windows(); len(got) != 1 {
+		t.Fatalf("managing %v after sending a away", got)
+	}
+	h.exec("workspace 2")
+	if w, _ := h.b.Window(a); w.Hidden {
+		t.Fatal("a is still hidden on its own workspace")
+	}
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 1000, H: 500})
+}
+
+func TestForegroundManagesMissedWindow(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a := h.open("a")
+	b := h.b.Open(fake.Window{Title: "b", W: 300, H: 200})
+	h.drop()
+	if got := h.m.Windows(); len(got) != 1 {
+		t.Fatalf("managing %v before b came to the front", got)
+	}
+
+	h.b.Focus(a)
+	h.b.Focus(b)
+	h.settle()
+	if f := h.m.State().Focused; f != b {
+		t.Errorf("focused %#x, want %#x", f, b)
+	}
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+}
+
+func TestDragSnapsBack(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a := h.open("a")
+	h.open("b")
+
+	h.b.Drag(a, 600, 100)
+	h.settle()
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+}
+
+func TestSubscribe(t *testing.T) {
+	h := newHarness(t, Options{})
+	events, cancel := h.m.Subscribe()
+	h.m.Toggle()
+	a := h.open("a")
+
+	var got []string
+	for len(events) > 0 {
+		ev := <-events
+		got = append(got, ev.Type)
+		if ev.State == nil {
+			t.Fatalf("%s came without a state", ev.Type)
+		}
+		if ev.Type == EventFocusChanged && (ev.Hwnd != a || ev.State.Focused != a) {
+			t.Errorf("focus_changed for %#x with %#x focused, want %#x", ev.Hwnd, ev.State.Focused, a)
+		}
+	}
+	want := []string{EventTilingToggled, EventWindowManaged, EventFocusChanged}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Fatal("events still open after cancel")
+	}
+}
@@ -0,0 +1,76 @@
+package wm
+
+import "glo/layout"
+
+// ToggleFloat takes the focused window out of the layout, back to the rect
+// it had when it last floated (or before glo touched it), or puts a floating
+// window back into its slot
+func (m *WindowManager) ToggleFloat() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hwnd := m.focused
+	if _, ws := m.showing(hwnd); ws == nil || m.minimized[hwnd] {
+		return
+	}
+
+	m.setFloating(hwnd, !m.floating[hwnd])
+	if m.tiling {
+		m.tile()
+	}
+}
+
+// setFloating floats hwnd where it was the last time it floated, or where it
+// was before glo tiled it, or puts it back in the layout. m.mu must be held
+func (m *WindowManager) setFloating(hwnd uintptr, float bool) {
+	if !float {
+		x, y, width, height, err := m.b.GetRect(hwnd)
+		if err != nil {
+			// closed, it's about to be unmanaged anyway
+			return
+		}
+		m.floatRects[hwnd] = layout.Rect{X: x, Y: y, W: width, H: height}
+		delete(m.floating, hwnd)
+		return
+	}
+
+	m.floating[hwnd] = true
+	delete(m.fullscreen, hwnd)
+	if m.tiling && !m.minimized[hwnd] {
+		w := m.windows[hwnd]
+		r, ok := m.floatRects[hwnd]
+		if !ok {
+			r = layout.Rect{X: w.Meta.Ox, Y: w.Meta.Oy, W: w.Meta.Ow, H: w.Meta.Oh}
+		}
+		w.SetRect(r.X, r.Y, r.W, r.H)
+	}
+}
+
+func (m *WindowManager) Floating(hwnd uintptr) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.floating[hwnd]
+}
+
+// ToggleFullscreen blows the focused tiled window up to the whole work area
+// of its monitor, ignoring the gaps, or puts it back in its tile
+func (m *WindowManager) ToggleFullscreen() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hwnd := m.focused
+	if _, ws := m.showing(hwnd); ws == nil || m.floating[hwnd] {
+		return
+	}
+	if m.fullscreen[hwnd] {
+		delete(m.fullscreen, hwnd)
+	} else {
+		m.fullscreen[hwnd] = true
+	}
+
+	if m.tiling {
+		m.tile()
+		m.b.Focus(hwnd)
+	}
+}
@@ -0,0 +1,93 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/platform/fake"
+	"testing"
+	"time"
+)
+
+func TestToggleFloat(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a := h.open("a")
+	b := h.b.Open(fake.Window{Title: "b", X: 10, Y: 20, W: 300, H: 200})
+	h.settle()
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+
+	// floating goes back to where it was before glo touched it
+	h.exec("toggle-float")
+	h.wantRect(b, layout.Rect{X: 10, Y: 20, W: 300, H: 200})
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 1000, H: 500})
+
+	h.b.Drag(b, 40, 50)
+	h.settle()
+	h.exec("toggle-float")
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+
+	// and next time to where it was left
+	h.exec("toggle-float")
+	h.wantRect(b, layout.Rect{X: 40, Y: 50, W: 300, H: 200})
+}
+
+func TestToggleFloatClosedWindow(t *testing.T) {
+	b := &closing{fake.New(1000, 500), make(map[uintptr]bool)}
+	m := New(b, fake.NewClock(time.Unix(0, 0)), Options{MasterFrac: 0.5})
+	m.Toggle()
+	a := b.Open(fake.Window{Title: "a"})
+	m.Manage(a)
+	m.focus(a)
+
+	m.ToggleFloat()
+	b.gone[a] = true
+	m.ToggleFloat()
+	if !m.Floating(a) {
+		t.Fatal("a window that closed was put back in the layout")
+	}
+}
+
+func TestToggleFullscreen(t *testing.T) {
+	h := newHarness(t, Options{Gaps: layout.Gaps{Top: 10, Right: 10, Bottom: 10, Left: 10, Inner: 10}})
+	h.m.Toggle()
+	a := h.open("a")
+	b := h.open("b")
+	tileA, tileB := h.rect(a), h.rect(b)
+	if tileB == (layout.Rect{X: 500, Y: 0, W: 500, H: 500}) {
+		t.Fatalf("b at %v, the gaps weren't applied", tileB)
+	}
+
+	// the whole work area, gaps or not
+	h.exec("toggle-fullscreen")
+	h.wantRect(b, layout.Rect{X: 0, Y: 0, W: 1000, H: 500})
+	h.wantRect(a, tileA)
+	if h.b.Foreground() != b {
+		t.Error("the fullscreen window isn't in front")
+	}
+
+	// it stays that way when the layout changes under it
+	c := h.open("c")
+	h.wantRect(b, layout.Rect{X: 0, Y: 0, W: 1000, H: 500})
+	h.b.Focus(b)
+	h.settle()
+
+	h.b.Destroy(c)
+	h.settle()
+	h.exec("toggle-fullscreen")
+	h.wantRect(b, tileB)
+	h.wantRect(a, tileA)
+}
+
+func TestToggleFullscreenFloating(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	h.open("a")
+	b := h.b.Open(fake.Window{Title: "b", X: 10, Y: 20, W: 300, H: 200})
+	h.settle()
+	h.exec("toggle-float")
+
+	// a floating window is sized by hand, not by glo
+	h.exec("toggle-fullscreen")
+	h.wantRect(b, layout.Rect{X: 10, Y: 20, W: 300, H: 200})
+	h.exec("toggle-float")
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+}
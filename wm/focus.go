@@ -0,0 +1,168 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/window"
+)
+
+// FocusStep moves focus delta windows along the current workspace's layout
+// order, wrapping around
+func (m *WindowManager) FocusStep(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws := m.current().active()
+	if target := neighbour(m.visible(ws), m.focused, delta); target != 0 {
+		m.b.Focus(target)
+	}
+}
+
+// SwapStep swaps the focused window with the one delta places along the
+// layout order, focus stays with the window as it moves
+func (m *WindowManager) SwapStep(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ws := m.showing(m.focused)
+	if ws == nil {
+		return
+	}
+	if m.floating[m.focused] {
+		return
+	}
+	if target := neighbour(m.tiled(ws), m.focused, delta); target != 0 && target != m.focused {
+		ws.Swap(m.focused, target)
+		m.triggerTile()
+	}
+}
+
+// neighbour is the window delta places from hwnd in ws, or the first one if
+// hwnd isn't there
+func neighbour(ws []*window.Window, hwnd uintptr, delta int) uintptr {
+	if len(ws) == 0 {
+		return 0
+	}
+	for i, w := range ws {
+		if w.Hwnd() == hwnd {
+			n := len(ws)
+			return ws[((i+delta)%n+n)%n].Hwnd()
+		}
+	}
+	return ws[0].Hwnd()
+}
+
+// FocusDirection focuses the window next to the focused one on screen, going
+// by the layout's geometry (or where windows actually are with tiling off).
+// in the tree layout's tabbed and stacked containers it steps through the
+// tabs first. off the left or right edge it carries on to the next monitor
+// that way
+func (m *WindowManager) FocusDirection(d layout.Direction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mon, ws := m.showing(m.focused)
+	if ws == nil {
+		return
+	}
+	if t, _ := m.manual(); t != nil && m.tiling {
+		if target, ok := t.Tab(ws.Items(m.inLayout, m.weights), m.focused, d); ok {
+			m.b.Focus(target)
+			return
+		}
+	}
+	if target, ok := layout.Neighbour(m.geometry(mon), m.focused, d); ok {
+		m.b.Focus(target)
+		return
+	}
+	if next := m.monitorToward(mon, d); next != nil {
+		m.focusWorkspace(next.active())
+	}
+}
+
+// SwapDirection swaps the focused window with the one next to it on screen,
+// off the left or right edge it moves to the next monitor that way
+func (m *WindowManager) SwapDirection(d layout.Direction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mon, ws := m.showing(m.focused)
+	if ws == nil || m.floating[m.focused] {
+		return
+	}
+	if target, ok := layout.Neighbour(m.geometry(mon), m.focused, d); ok {
+		ws.Swap(m.focused, target)
+		m.triggerTile()
+		return
+	}
+	if m.monitorToward(mon, d) != nil {
+		if d == layout.Left {
+			m.moveToMonitor(-1)
+		} else {
+			m.moveToMonitor(1)
+		}
+	}
+}
+
+// Promote is dwm's zoom: the focused window becomes master, or if it
+// already is it swaps with the first window of the stack
+func (m *WindowManager) Promote() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ws := m.showing(m.focused)
+	if ws == nil || m.floating[m.focused] {
+		return
+	}
+	tiled := m.tiled(ws)
+	if len(tiled) < 2 {
+		return
+	}
+
+	if tiled[0].Hwnd() == m.focused {
+		ws.Swap(m.focused, tiled[1].Hwnd())
+	} else {
+		ws.Promote(m.focused)
+	}
+	m.triggerTile()
+}
+
+// geometry is where the tiled windows on mon are, or would be
+func (m *WindowManager) geometry(mon *monitor) layout.Plan {
+	if m.tiling {
+		return m.plan(mon)
+	}
+
+	var plan layout.Plan
+	for _, w := range m.tiled(mon.active()) {
+		// windows that just closed have nowhere to be
+		x, y, width, height, err := m.b.GetRect(w.Hwnd())
+		if err != nil {
+			continue
+		}
+		plan = append(plan, layout.Placement{ID: w.Hwnd(), Rect: layout.Rect{X: x, Y: y, W: width, H: height}})
+	}
+	return plan
+}
+
+// monitorToward is the monitor beside mon going d, monitors only sit side
+// by side so that's nil for up and down and at either end
+func (m *WindowManager) monitorToward(mon *monitor, d layout.Direction) *monitor {
+	delta := 0
+	switch d {
+	case layout.Left:
+		delta = -1
+	case layout.Right:
+		delta = 1
+	default:
+		return nil
+	}
+	for i, other := range m.monitors {
+		if other == mon {
+			if j := i + delta; j >= 0 && j < len(m.monitors) {
+				return m.monitors[j]
+			}
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/platform/fake"
+	"testing"
+	"time"
+)
+
+func TestFocusDirection(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a := h.open("a")
+	b := h.open("b")
+	c := h.open("c")
+
+	for _, step := range []struct {
+		command string
+		want    uintptr
+	}{
+		{"focus left", a},
+		{"focus right", b},
+		{"focus down", c},
+		{"focus up", b},
+		{"focus next", c},
+		{"focus next", a},
+		{"focus prev", c},
+	} {
+		h.exec(step.command)
+		if got := h.b.Foreground(); got != step.want {
+			t.Fatalf("%s focused %#x, want %#x", step.command, got, step.want)
+		}
+	}
+}
+
+func TestSwapAndPromote(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a := h.open("a")
+	b := h.open("b")
+	c := h.open("c")
+
+	h.exec("swap left")
+	if got := h.m.Windows(); got[0] != c || got[2] != a {
+		t.Fatalf("after swap left order is %v", got)
+	}
+	h.exec("promote")
+	if got := h.m.Windows(); got[0] != b || got[1] != c || got[2] != a {
+		t.Fatalf("after promote order is %v", got)
+	}
+}
+
+func TestFocusDirectionUntiledClosedWindow(t *testing.T) {
+	b := &closing{fake.New(1000, 500), make(map[uintptr]bool)}
+	m := New(b, fake.NewClock(time.Unix(0, 0)), Options{MasterFrac: 0.5})
+	left := b.Open(fake.Window{Title: "left", X: 0, W: 400, H: 400})
+	gone := b.Open(fake.Window{Title: "gone", X: 500, W: 400, H: 400})
+	m.Manage(left)
+	m.Manage(gone)
+	b.Focus(left)
+	m.focus(left)
+
+	b.gone[gone] = true
+	m.FocusDirection(layout.Right)
+	if b.Foreground() != left {
+		t.Fatal("focus moved to a window that closed")
+	}
+}
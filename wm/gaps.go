@@ -0,0 +1,80 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/workspace"
+)
+
+// gapStep is how many pixels gaps inc and gaps dec move a gap by
+const gapStep = 5
+
+// gapParts are the gaps the gaps command can pick, outer is all four
+// sides. without one it changes every gap
+var gapParts = []string{"inner", "outer", "top", "right", "bottom", "left"}
+
+// IncGaps widens part of the current workspace's gaps, every gap if part is
+// empty
+func (m *WindowManager) IncGaps(part string) {
+	m.adjustGaps(part, gapStep)
+}
+
+func (m *WindowManager) DecGaps(part string) {
+	m.adjustGaps(part, -gapStep)
+}
+
+func (m *WindowManager) adjustGaps(part string, delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws := m.current().active()
+	g := ws.Gaps
+	for _, n := range gapFields(&g, part) {
+		*n = max(*n+delta, 0)
+	}
+	m.setGaps(ws, g)
+}
+
+// ResetGaps puts part of the current workspace's gaps back to the configured
+// ones, every gap if part is empty
+func (m *WindowManager) ResetGaps(part string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws := m.current().active()
+	g, def := ws.Gaps, m.gaps
+	from := gapFields(&def, part)
+	for i, n := range gapFields(&g, part) {
+		*n = *from[i]
+	}
+	m.setGaps(ws, g)
+}
+
+// setGaps gives ws gaps g and retiles if that changed anything, m.mu must be
+// held
+func (m *WindowManager) setGaps(ws *workspace.Workspace, g layout.Gaps) {
+	if g == ws.Gaps {
+		return
+	}
+	ws.Gaps = g
+	m.emit(Event{Type: EventGapsChanged, Gaps: &g})
+	m.triggerTile()
+}
+
+// gapFields are the gaps in g that part names
+func gapFields(g *layout.Gaps, part string) []*int {
+	switch part {
+	case "inner":
+		return []*int{&g.Inner}
+	case "outer":
+		return []*int{&g.Top, &g.Right, &g.Bottom, &g.Left}
+	case "top":
+		return []*int{&g.Top}
+	case "right":
+		return []*int{&g.Right}
+	case "bottom":
+		return []*int{&g.Bottom}
+	case "left":
+		return []*int{&g.Left}
+	}
+	return []*int{&g.Top, &g.Right, &g.Bottom, &g.Left, &g.Inner}
+}
@@ -0,0 +1,94 @@
+package wm
+
+import (
+	"glo/layout"
+	"testing"
+)
+
+func TestGapsCommand(t *testing.T) {
+	configured := layout.Gaps{Top: 10, Right: 10, Bottom: 10, Left: 10, Inner: 10}
+	for _, tc := range []struct {
+		lines []string
+		want  layout.Gaps
+	}{
+		{[]string{"gaps inc"}, layout.Gaps{Top: 15, Right: 15, Bottom: 15, Left: 15, Inner: 15}},
+		{[]string{"gaps dec"}, layout.Gaps{Top: 5, Right: 5, Bottom: 5, Left: 5, Inner: 5}},
+		{[]string{"gaps inc inner"}, layout.Gaps{Top: 10, Right: 10, Bottom: 10, Left: 10, Inner: 15}},
+		{[]string{"gaps inc outer"}, layout.Gaps{Top: 15, Right: 15, Bottom: 15, Left: 15, Inner: 10}},
+		{[]string{"gaps inc top", "gaps dec left"}, layout.Gaps{Top: 15, Right: 10, Bottom: 10, Left: 5, Inner: 10}},
+		{[]string{"gaps inc right", "gaps inc bottom"}, layout.Gaps{Top: 10, Right: 15, Bottom: 15, Left: 10, Inner: 10}},
+		// never below zero
+		{[]string{"gaps dec", "gaps dec", "gaps dec"}, layout.Gaps{}},
+		{[]string{"gaps inc", "gaps inc top", "gaps reset inner"}, layout.Gaps{Top: 20, Right: 15, Bottom: 15, Left: 15, Inner: 10}},
+		{[]string{"gaps inc", "gaps inc top", "gaps reset top"}, layout.Gaps{Top: 10, Right: 15, Bottom: 15, Left: 15, Inner: 15}},
+		{[]string{"gaps inc", "gaps reset outer"}, layout.Gaps{Top: 10, Right: 10, Bottom: 10, Left: 10, Inner: 15}},
+		{[]string{"gaps inc", "gaps dec inner", "gaps reset"}, configured},
+	} {
+		h := newHarness(t, Options{Gaps: configured})
+		for _, line := range tc.lines {
+			h.exec(line)
+		}
+		if got := h.m.State().Gaps; got != tc.want {
+			t.Errorf("%q: gaps are %+v, want %+v", tc.lines, got, tc.want)
+		}
+	}
+}
+
+func TestGapsCommandErrors(t *testing.T) {
+	h := newHarness(t, Options{})
+	for _, line := range []string{"gaps", "gaps wider", "gaps inc middle", "gaps inc top left"} {
+		if err := h.m.Exec(line); err == nil {
+			t.Errorf("%q: no error", line)
+		}
+	}
+}
+
+func TestGapsRetile(t *testing.T) {
+	h := newHarness(t, Options{Gaps: layout.Gaps{}.Outer(10)})
+	h.m.Toggle()
+	a := h.open("a")
+	h.wantRect(a, layout.Rect{X: 10, Y: 10, W: 980, H: 480})
+
+	h.exec("gaps inc left")
+	h.wantRect(a, layout.Rect{X: 15, Y: 10, W: 975, H: 480})
+	h.exec("gaps reset")
+	h.wantRect(a, layout.Rect{X: 10, Y: 10, W: 980, H: 480})
+}
+
+func TestGapsPerWorkspace(t *testing.T) {
+	configured := layout.Gaps{}.Outer(10)
+	h := newHarness(t, Options{Gaps: configured, Layout: "master-stack"})
+	h.m.Toggle()
+	h.exec("gaps inc")
+	changed := h.m.State().Gaps
+
+	// only the workspace they were changed on
+	h.exec("workspace 2")
+	if got := h.m.State().Gaps; got != configured {
+		t.Fatalf("workspace 2 has gaps %+v, want the configured %+v", got, configured)
+	}
+	h.exec("workspace 1")
+	if got := h.m.State().Gaps; got != changed {
+		t.Fatalf("workspace 1 has gaps %+v, want %+v", got, changed)
+	}
+
+	// a reload that leaves the configured gaps alone keeps the change
+	h.m.Configure(Options{MasterFrac: 0.5, Gaps: configured, Layout: "master-stack"})
+	if got := h.m.State().Gaps; got != changed {
+		t.Fatalf("after a reload workspace 1 has gaps %+v, want %+v", got, changed)
+	}
+
+	// new configured gaps replace it everywhere
+	wider := layout.Gaps{}.Outer(20)
+	h.m.Configure(Options{MasterFrac: 0.5, Gaps: wider, Layout: "master-stack"})
+	if got := h.m.State().Gaps; got != wider {
+		t.Fatalf("after new gaps workspace 1 has %+v, want %+v", got, wider)
+	}
+
+	// and reset goes back to the new ones
+	h.exec("gaps dec")
+	h.exec("gaps reset")
+	if got := h.m.State().Gaps; got != wider {
+		t.Fatalf("reset to %+v, want %+v", got, wider)
+	}
+}
@@ -0,0 +1,68 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/workspace"
+)
+
+// Split puts the focused window in a new container of the tree layout, so
+// the next window opened next to it shares its space the way s says
+func (m *WindowManager) Split(s layout.Split) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ws := m.manual()
+	if t != nil && t.Split(ws.Items(m.inLayout, m.weights), m.focused, s) {
+		m.triggerTile()
+	}
+}
+
+// SetContainer changes how the focused window's container in the tree layout
+// splits its space
+func (m *WindowManager) SetContainer(s layout.Split) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ws := m.manual()
+	if t != nil && t.SetSplit(ws.Items(m.inLayout, m.weights), m.focused, s) {
+		m.triggerTile()
+	}
+}
+
+// ToggleContainer flips the focused window's container in the tree layout
+// between horizontal and vertical
+func (m *WindowManager) ToggleContainer() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ws := m.manual()
+	if t != nil && t.ToggleSplit(ws.Items(m.inLayout, m.weights), m.focused) {
+		m.triggerTile()
+	}
+}
+
+// Move takes the focused window a step through the tree layout in
+// direction d
+func (m *WindowManager) Move(d layout.Direction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ws := m.manual()
+	if t != nil && t.Move(ws.Items(m.inLayout, m.weights), m.focused, d) {
+		m.triggerTile()
+	}
+}
+
+// manual is the showing workspace of the focused window if its layout is a
+// layout.Manual, m.mu must be held
+func (m *WindowManager) manual() (layout.Manual, *workspace.Workspace) {
+	_, ws := m.showing(m.focused)
+	if ws == nil || !m.inLayout(m.focused) {
+		return nil, nil
+	}
+	t, ok := ws.Layout.(layout.Manual)
+	if !ok {
+		return nil, nil
+	}
+	return t, ws
+}
@@ -0,0 +1,96 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/platform"
+	"testing"
+)
+
+// nested opens a | (b / c) on the tree layout
+func nested(t *testing.T) (h *harness, a, b, c uintptr) {
+	h = newHarness(t, Options{Layout: "tree"})
+	h.m.Toggle()
+	a, b = h.open("a"), h.open("b")
+	h.exec("split vertical")
+	c = h.open("c")
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 250})
+	h.wantRect(c, layout.Rect{X: 500, Y: 250, W: 500, H: 250})
+	return h, a, b, c
+}
+
+func TestTreeKeepsMinimizedSlot(t *testing.T) {
+	h, a, b, c := nested(t)
+
+	h.b.ShowWindow(b, platform.SW_SHOWMINIMIZED)
+	h.settle()
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(c, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+
+	h.b.Focus(a)
+	h.settle()
+	h.b.ShowWindow(b, platform.SW_SHOWNORMAL)
+	h.settle()
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 250})
+	h.wantRect(c, layout.Rect{X: 500, Y: 250, W: 500, H: 250})
+}
+
+func TestTreeKeepsFloatingSlot(t *testing.T) {
+	h, a, b, c := nested(t)
+
+	h.b.Focus(b)
+	h.settle()
+	h.exec("toggle-float")
+	h.wantRect(c, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+
+	h.b.Focus(b)
+	h.settle()
+	h.exec("toggle-float")
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 250})
+	h.wantRect(c, layout.Rect{X: 500, Y: 250, W: 500, H: 250})
+}
+
+func TestFocusTabs(t *testing.T) {
+	h, a, b, c := nested(t)
+	h.b.Focus(b)
+	h.settle()
+
+	h.exec("container tabbed")
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+	h.wantRect(c, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+
+	for _, step := range []struct {
+		dir  string
+		want uintptr
+	}{
+		{"right", c},
+		// the last tab, and nothing on screen further right
+		{"right", c},
+		{"left", b},
+		// past the first tab on to the window beside the container
+		{"left", a},
+		{"right", b},
+	} {
+		h.exec("focus " + step.dir)
+		if f := h.b.Foreground(); f != step.want {
+			t.Fatalf("focus %s went to %#x, want %#x", step.dir, f, step.want)
+		}
+	}
+
+	// stacked tabs go up and down instead
+	h.exec("container stacked")
+	h.exec("focus right")
+	if f := h.b.Foreground(); f != b {
+		t.Fatalf("focus right in a stack went to %#x", f)
+	}
+	h.exec("focus down")
+	if f := h.b.Foreground(); f != c {
+		t.Fatalf("focus down in a stack went to %#x, want %#x", f, c)
+	}
+	h.exec("focus up")
+	if f := h.b.Foreground(); f != b {
+		t.Fatalf("focus up in a stack went to %#x, want %#x", f, b)
+	}
+}
@@ -0,0 +1,179 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/window"
+	"glo/workspace"
+)
+
+// monitor is one display and its own row of workspaces
+type monitor struct {
+	handle  uintptr
+	primary bool
+	area    layout.Rect
+	// the whole screen, taskbar included
+	bounds layout.Rect
+
+	workspaces *workspace.Set
+}
+
+func (mon *monitor) active() *workspace.Workspace {
+	return mon.workspaces.Active()
+}
+
+// refreshMonitors syncs m.monitors with the displays that exist right now.
+// windows on a monitor that went away move to the same workspace on the
+// primary one
+func (m *WindowManager) refreshMonitors() {
+	found := m.b.Monitors()
+	if len(found) == 0 {
+		return
+	}
+
+	old := make(map[uintptr]*monitor, len(m.monitors))
+	for _, mon := range m.monitors {
+		old[mon.handle] = mon
+	}
+
+	monitors := make([]*monitor, 0, len(found))
+	for _, pm := range found {
+		mon, ok := old[pm.Handle]
+		area := layout.Rect{X: pm.WorkX, Y: pm.WorkY, W: pm.WorkW, H: pm.WorkH}
+		if !ok {
+			mon = &monitor{handle: pm.Handle, workspaces: m.newWorkspaces(area)}
+		}
+		delete(old, pm.Handle)
+		mon.primary = pm.Primary
+		mon.area = area
+		mon.bounds = layout.Rect{X: pm.X, Y: pm.Y, W: pm.W, H: pm.H}
+		monitors = append(monitors, mon)
+	}
+	m.monitors = monitors
+
+	if len(old) == 0 {
+		return
+	}
+	primary := m.primary()
+	for _, gone := range old {
+		for _, ws := range gone.workspaces.All() {
+			for _, hwnd := range ws.Windows() {
+				primary.workspaces.Get(ws.ID).Add(hwnd)
+			}
+		}
+	}
+	m.syncVisibility()
+}
+
+func (m *WindowManager) primary() *monitor {
+	for _, mon := range m.monitors {
+		if mon.primary {
+			return mon
+		}
+	}
+	return m.monitors[0]
+}
+
+// monitorOf returns the monitor hwnd is physically on
+func (m *WindowManager) monitorOf(hwnd uintptr) *monitor {
+	h := m.b.MonitorFromWindow(hwnd)
+	for _, mon := range m.monitors {
+		if mon.handle == h {
+			return mon
+		}
+	}
+	return m.primary()
+}
+
+// find returns the monitor and workspace hwnd is assigned to
+func (m *WindowManager) find(hwnd uintptr) (*monitor, *workspace.Workspace) {
+	for _, mon := range m.monitors {
+		if ws := mon.workspaces.Find(hwnd); ws != nil {
+			return mon, ws
+		}
+	}
+	return nil, nil
+}
+
+// showing is find for windows on the workspace showing on their monitor, the
+// only ones commands should touch. nil for the rest
+func (m *WindowManager) showing(hwnd uintptr) (*monitor, *workspace.Workspace) {
+	mon, ws := m.find(hwnd)
+	if ws == nil || ws != mon.active() {
+		return nil, nil
+	}
+	return mon, ws
+}
+
+// current is the monitor commands act on, the one with the focused window
+func (m *WindowManager) current() *monitor {
+	if mon, _ := m.find(m.focused); mon != nil {
+		return mon
+	}
+	if fg := m.b.Foreground(); fg != 0 {
+		return m.monitorOf(fg)
+	}
+	return m.primary()
+}
+
+func (m *WindowManager) step(mon *monitor, delta int) *monitor {
+	n := len(m.monitors)
+	for i, other := range m.monitors {
+		if other == mon {
+			return m.monitors[((i+delta)%n+n)%n]
+		}
+	}
+	return mon
+}
+
+// MoveToMonitor sends the focused window delta monitors to the right,
+// wrapping around at either end. it lands on that monitor's active workspace
+func (m *WindowManager) MoveToMonitor(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.moveToMonitor(delta)
+}
+
+func (m *WindowManager) moveToMonitor(delta int) {
+	from, ws := m.showing(m.focused)
+	if ws == nil {
+		return
+	}
+	to := m.step(from, delta)
+	if to == from {
+		return
+	}
+
+	hwnd := m.focused
+	ws.Remove(hwnd)
+	to.active().Add(hwnd)
+	to.active().Focus(hwnd)
+
+	if m.tiling {
+		m.tile()
+		if !m.floating[hwnd] {
+			return
+		}
+	}
+	m.moveOnto(m.windows[hwnd], from, to)
+}
+
+// moveOnto moves w from one monitor to the same spot relative to the other
+// monitor's work area
+func (m *WindowManager) moveOnto(w *window.Window, from, to *monitor) {
+	x, y, width, height, err := m.b.GetRect(w.Hwnd())
+	if err != nil {
+		return
+	}
+	nx := to.area.X + (x-from.area.X)*to.area.W/max(from.area.W, 1)
+	ny := to.area.Y + (y-from.area.Y)*to.area.H/max(from.area.H, 1)
+	w.SetRect(nx, ny, min(width, to.area.W), min(height, to.area.H))
+}
+
+// FocusMonitor focuses the window last used delta monitors to the right
+func (m *WindowManager) FocusMonitor(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.focusWorkspace(m.step(m.current(), delta).active())
+}
@@ -0,0 +1,57 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/platform"
+	"glo/platform/fake"
+	"testing"
+	"time"
+)
+
+var twoMonitors = []platform.Monitor{
+	{Handle: 1, Primary: true, W: 1000, H: 500, WorkW: 1000, WorkH: 500},
+	{Handle: 2, X: 1000, W: 2000, H: 1000, WorkX: 1000, WorkW: 2000, WorkH: 1000},
+}
+
+func TestMonitorsTileSeparately(t *testing.T) {
+	h := newHarness(t, Options{}, twoMonitors...)
+	h.m.Toggle()
+	a := h.open("a")
+	b := h.open("b")
+	h.exec("move-to-monitor next")
+
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 1000, H: 500})
+	h.wantRect(b, layout.Rect{X: 1000, Y: 0, W: 2000, H: 1000})
+
+	// and back, wrapping around
+	h.exec("move-to-monitor next")
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+}
+
+func TestMoveFloatingToMonitor(t *testing.T) {
+	h := newHarness(t, Options{}, twoMonitors...)
+	h.m.Toggle()
+	a := h.b.Open(fake.Window{Title: "a", X: 100, Y: 50, W: 300, H: 200})
+	h.settle()
+	h.exec("toggle-float")
+	h.exec("move-to-monitor next")
+
+	// same spot relative to the work area
+	h.wantRect(a, layout.Rect{X: 1200, Y: 100, W: 300, H: 200})
+}
+
+func TestMoveClosedFloatingWindow(t *testing.T) {
+	b := &closing{fake.New(1000, 500), make(map[uintptr]bool)}
+	b.SetMonitors(twoMonitors...)
+	m := New(b, fake.NewClock(time.Unix(0, 0)), Options{MasterFrac: 0.5})
+	a := b.Open(fake.Window{Title: "a", X: 100, Y: 50, W: 300, H: 200})
+	m.Manage(a)
+	m.focus(a)
+
+	b.gone[a] = true
+	m.MoveToMonitor(1)
+	if mon, _ := m.find(a); mon == nil || mon.handle != 2 {
+		t.Fatal("a didn't move to the second monitor")
+	}
+}
@@ -0,0 +1,192 @@
+package wm
+
+import (
+	"fmt"
+	"glo/layout"
+	"glo/workspace"
+	"sort"
+)
+
+// WindowInfo describes one managed window for glo query
+type WindowInfo struct {
+	Hwnd    uintptr `json:"hwnd"`
+	Title   string  `json:"title"`
+	Class   string  `json:"class"`
+	Process string  `json:"process"`
+	PID     uint32  `json:"pid"`
+
+	Rect layout.Rect `json:"rect"`
+	// where the window was before glo touched it
+	Original layout.Rect `json:"original"`
+
+	Minimized  bool `json:"minimized"`
+	Floating   bool `json:"floating"`
+	Fullscreen bool `json:"fullscreen"`
+	Hidden     bool `json:"hidden"`
+	// share of the space it splits with its neighbours, 1 unless changed
+	Weight float64 `json:"weight"`
+
+	Monitor   uintptr `json:"monitor"`
+	Workspace int     `json:"workspace"`
+	// position in the workspace's window order, 0 is master. minimized and
+	// floating windows keep theirs, -1 means the window isn't on a workspace
+	Slot int `json:"slot"`
+}
+
+type MonitorInfo struct {
+	Handle    uintptr     `json:"handle"`
+	Primary   bool        `json:"primary"`
+	Area      layout.Rect `json:"area"`
+	Workspace int         `json:"workspace"`
+}
+
+type WorkspaceInfo struct {
+	Monitor     uintptr     `json:"monitor"`
+	ID          int         `json:"id"`
+	Active      bool        `json:"active"`
+	Layout      string      `json:"layout"`
+	MasterFrac  float64     `json:"master_frac"`
+	NMaster     int         `json:"nmaster"`
+	Orientation string      `json:"orientation"`
+	Gaps        layout.Gaps `json:"gaps"`
+	Focused     uintptr     `json:"focused"`
+	Windows     []uintptr   `json:"windows"`
+	// the containers of the tree layout, nil for every other layout
+	Tree *layout.Node `json:"tree,omitempty"`
+}
+
+var Queries = []string{"windows", "monitors", "workspaces", "tree", "state"}
+
+// Query returns one of Queries, ready to be marshalled
+func (m *WindowManager) Query(what string) (any, error) {
+	switch what {
+	case "windows":
+		return m.QueryWindows(), nil
+	case "monitors":
+		return m.QueryMonitors(), nil
+	case "workspaces":
+		return m.QueryWorkspaces(), nil
+	case "tree":
+		return m.QueryTree(), nil
+	case "state":
+		return m.State(), nil
+	}
+	return nil, fmt.Errorf("unknown query %q (have %v)", what, Queries)
+}
+
+// QueryWindows lists every managed window ordered by monitor, workspace and
+// slot
+func (m *WindowManager) QueryWindows() []WindowInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]WindowInfo, 0, len(m.windows))
+	for hwnd, w := range m.windows {
+		info := WindowInfo{
+			Hwnd:       hwnd,
+			Title:      w.Title(),
+			Class:      w.Class,
+			Process:    w.ProcessName,
+			PID:        w.PID,
+			Original:   layout.Rect{X: w.Meta.Ox, Y: w.Meta.Oy, W: w.Meta.Ow, H: w.Meta.Oh},
+			Minimized:  m.minimized[hwnd],
+			Floating:   m.floating[hwnd],
+			Fullscreen: m.fullscreen[hwnd],
+			Hidden:     m.hidden[hwnd],
+			Weight:     1,
+			Slot:       -1,
+		}
+		if wt, ok := m.weights[hwnd]; ok {
+			info.Weight = wt
+		}
+		// not w.GetRect, that panics if the window died a moment ago
+		if x, y, width, height, err := m.b.GetRect(hwnd); err == nil {
+			info.Rect = layout.Rect{X: x, Y: y, W: width, H: height}
+		}
+		if mon, ws := m.find(hwnd); ws != nil {
+			info.Monitor, info.Workspace, info.Slot = mon.handle, ws.ID, ws.Index(hwnd)
+		} else {
+			info.Monitor = m.monitorOf(hwnd).handle
+		}
+		out = append(out, info)
+	}
+
+	order := make(map[uintptr]int, len(m.monitors))
+	for i, mon := range m.monitors {
+		order[mon.handle] = i
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if (a.Slot < 0) != (b.Slot < 0) {
+			return b.Slot < 0
+		}
+		if a.Monitor != b.Monitor {
+			return order[a.Monitor] < order[b.Monitor]
+		}
+		if a.Workspace != b.Workspace {
+			return a.Workspace < b.Workspace
+		}
+		if a.Slot != b.Slot {
+			return a.Slot < b.Slot
+		}
+		return a.Hwnd < b.Hwnd
+	})
+	return out
+}
+
+func (m *WindowManager) QueryMonitors() []MonitorInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]MonitorInfo, 0, len(m.monitors))
+	for _, mon := range m.monitors {
+		out = append(out, MonitorInfo{
+			Handle:    mon.handle,
+			Primary:   mon.primary,
+			Area:      mon.area,
+			Workspace: mon.active().ID,
+		})
+	}
+	return out
+}
+
+func (m *WindowManager) QueryWorkspaces() []WorkspaceInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []WorkspaceInfo
+	for _, mon := range m.monitors {
+		for _, ws := range mon.workspaces.All() {
+			out = append(out, WorkspaceInfo{
+				Monitor:     mon.handle,
+				ID:          ws.ID,
+				Active:      ws == mon.active(),
+				Layout:      ws.Layout.Name(),
+				MasterFrac:  ws.MasterFrac,
+				NMaster:     ws.NMaster,
+				Orientation: ws.Orientation.String(),
+				Gaps:        ws.Gaps,
+				Focused:     ws.Focused(),
+				Windows:     append([]uintptr{}, ws.Windows()...),
+				Tree:        tree(ws),
+			})
+		}
+	}
+	return out
+}
+
+// QueryTree is the container tree of the current workspace, nil unless it
+// uses the tree layout
+func (m *WindowManager) QueryTree() *layout.Node {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return tree(m.current().active())
+}
+
+func tree(ws *workspace.Workspace) *layout.Node {
+	if t, ok := ws.Layout.(layout.Manual); ok {
+		return t.Root()
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package wm
+
+import (
+	"encoding/json"
+	"glo/platform"
+	"slices"
+	"testing"
+)
+
+// query runs what through Query and JSON the way glo query sends it
+func (h *harness) query(what string) string {
+	h.t.Helper()
+	v, err := h.m.Query(what)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		h.t.Fatalf("query %s: %v", what, err)
+	}
+	return string(out)
+}
+
+// querySetup is a | (b / c / d) on the tree layout with b minimized, c grown
+// and d sent to workspace 2
+func querySetup(t *testing.T) *harness {
+	h, _, b, c := nested(t)
+	h.b.Focus(c)
+	h.settle()
+	h.exec("grow-window")
+	h.open("d")
+	h.exec("send-to-workspace 2")
+	h.b.ShowWindow(b, platform.SW_SHOWMINIMIZED)
+	h.settle()
+	h.b.Focus(0x10)
+	h.settle()
+	return h
+}
+
+func TestQueryWindows(t *testing.T) {
+	h := querySetup(t)
+
+	var got []struct {
+		Hwnd      uintptr `json:"hwnd"`
+		Title     string  `json:"title"`
+		Minimized bool    `json:"minimized"`
+		Hidden    bool    `json:"hidden"`
+		Weight    float64 `json:"weight"`
+		Monitor   uintptr `json:"monitor"`
+		Workspace int     `json:"workspace"`
+		Slot      int     `json:"slot"`
+	}
+	if err := json.Unmarshal([]byte(h.query("windows")), &got); err != nil {
+		t.Fatal(err)
+	}
+	type want struct {
+		title             string
+		minimized, hidden bool
+		weight            float64
+		workspace, slot   int
+	}
+	wants := []want{
+		{"a", false, false, 1, 1, 0},
+		{"b", true, false, 1, 1, 1},
+		{"c", false, false, 1.25, 1, 2},
+		{"d", false, true, 1, 2, 0},
+	}
+	if len(got) != len(wants) {
+		t.Fatalf("got %+v", got)
+	}
+	for i, w := range wants {
+		g := got[i]
+		if g.Title != w.title || g.Minimized != w.minimized || g.Hidden != w.hidden || g.Weight != w.weight ||
+			g.Monitor != 1 || g.Workspace != w.workspace || g.Slot != w.slot {
+			t.Errorf("window %d is %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestQueryMonitors(t *testing.T) {
+	h := querySetup(t)
+	want := `[{"handle":1,"primary":true,"area":{"x":0,"y":0,"w":1000,"h":500},"workspace":1}]`
+	if got := h.query("monitors"); got != want {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestQueryWorkspaces(t *testing.T) {
+	h := querySetup(t)
+
+	var got []WorkspaceInfo
+	if err := json.Unmarshal([]byte(h.query("workspaces")), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != WorkspaceCount {
+		t.Fatalf("%d workspaces", len(got))
+	}
+	one, two := got[0], got[1]
+	if one.ID != 1 || !one.Active || one.Layout != "tree" || one.Focused != 0x10 || !slices.Equal(one.Windows, []uintptr{0x10, 0x20, 0x30}) {
+		t.Errorf("workspace 1 is %+v", one)
+	}
+	if one.Tree == nil || len(one.Tree.Children) != 2 {
+		t.Errorf("workspace 1 has tree %+v", one.Tree)
+	}
+	if two.ID != 2 || two.Active || !slices.Equal(two.Windows, []uintptr{0x40}) || two.Tree != nil {
+		t.Errorf("workspace 2 is %+v", two)
+	}
+	for _, ws := range got[2:] {
+		// empty, not null, so scripts can iterate it
+		if ws.Windows == nil || len(ws.Windows) != 0 {
+			t.Errorf("workspace %d is %+v", ws.ID, ws)
+		}
+	}
+}
+
+func TestQueryTree(t *testing.T) {
+	h := querySetup(t)
+	// b keeps its place while it's minimized
+	want := `{"split":"horizontal","children":[{"window":16},{"split":"vertical","children":[{"window":32},{"window":48}]}]}`
+	if got := h.query("tree"); got != want {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+
+	h.exec("layout master-stack")
+	if got := h.query("tree"); got != "null" {
+		t.Fatalf("got %s off the tree layout", got)
+	}
+}
+
+func TestQueryState(t *testing.T) {
+	h := querySetup(t)
+	want := `{"tiling":true,"focused":16,"monitor":1,"workspace":1,"layout":"tree","master_frac":0.5,"nmaster":1,"orientation":"left",` +
+		`"gaps":{"top":0,"right":0,"bottom":0,"left":0,"inner":0},"monitors":[{"handle":1,"primary":true,"workspace":1,"occupied":[1,2]}]}`
+	if got := h.query("state"); got != want {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestQueryUnknown(t *testing.T) {
+	h := newHarness(t, Options{})
+	if _, err := h.m.Query("everything"); err == nil {
+		t.Fatal("no error")
+	}
+}
@@ -0,0 +1,74 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/platform"
+	"glo/rules"
+)
+
+type size struct {
+	W, H int
+}
+
+// ruleFor returns the first rule matching hwnd, m.mu must be held
+func (m *WindowManager) ruleFor(hwnd uintptr) (rules.Rule, bool) {
+	if len(m.rules) == 0 {
+		return rules.Rule{}, false
+	}
+	info := m.b.Info(hwnd)
+	w := rules.Window{
+		Process: info.Process,
+		Class:   info.Class,
+		Title:   info.Title,
+		Style:   info.Style,
+		ExStyle: info.ExStyle,
+	}
+	if _, _, width, height, err := m.b.GetRect(hwnd); err == nil {
+		w.W, w.H = width, height
+	}
+	return m.rules.Match(w)
+}
+
+// fixSizes shrinks each placement of a window with a fixed size to that
+// size, centered in the space the layout gave it
+func (m *WindowManager) fixSizes(plan layout.Plan) {
+	for i := range plan {
+		if sz, ok := m.fixed[plan[i].ID]; ok {
+			plan[i].Rect = centered(plan[i].Rect, sz)
+		}
+	}
+}
+
+// centered is sz centered in r, never bigger than r
+func centered(r layout.Rect, sz size) layout.Rect {
+	w, h := min(sz.W, r.W), min(sz.H, r.H)
+	return layout.Rect{X: r.X + (r.W-w)/2, Y: r.Y + (r.H-h)/2, W: w, H: h}
+}
+
+// reapplyRules runs the rules over the windows already managed, after they
+// changed. a window a rule now ignores is let go, float and tile rules float
+// or tile a window, and size rules fix or free its size. a window that
+// matches no rule keeps the floating it has, it may have been toggled by
+// hand. workspace, monitor and master only pick where a new window goes, so
+// managed windows stay where they are. m.mu must be held
+func (m *WindowManager) reapplyRules() {
+	for hwnd := range m.windows {
+		rule, _ := m.ruleFor(hwnd)
+		if rule.Ignore {
+			if m.hidden[hwnd] {
+				m.b.ShowWindow(hwnd, platform.SW_SHOWNOACTIVATE)
+			}
+			m.unmanage(hwnd)
+			m.ignored[hwnd] = true
+			continue
+		}
+		if rule.Float != m.floating[hwnd] && (rule.Float || rule.Tile) {
+			m.setFloating(hwnd, rule.Float)
+		}
+		if rule.Width > 0 {
+			m.fixed[hwnd] = size{rule.Width, rule.Height}
+		} else {
+			delete(m.fixed, hwnd)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/platform/fake"
+	"glo/rules"
+	"strings"
+	"testing"
+)
+
+func ruleSet(t *testing.T, lines ...string) rules.Set {
+	t.Helper()
+	var set rules.Set
+	for _, line := range lines {
+		r, err := rules.Parse(strings.Fields(line))
+		if err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+		set = append(set, r)
+	}
+	return set
+}
+
+func TestRulesOnManage(t *testing.T) {
+	h := newHarness(t, Options{Rules: ruleSet(t,
+		"class=Dialog float",
+		"process=tray.exe ignore",
+		"title=b* size 200x100",
+	)})
+	h.m.Toggle()
+	a := h.open("a")
+	dialog := h.b.Open(fake.Window{Title: "dialog", Class: "Dialog", X: 10, Y: 20, W: 300, H: 200})
+	tray := h.b.Open(fake.Window{Title: "tray", Process: "tray.exe", W: 300, H: 200})
+	b := h.open("b")
+
+	if !h.m.Floating(dialog) {
+		t.Error("the dialog isn't floating")
+	}
+	if got := h.m.Windows(); len(got) != 3 {
+		t.Errorf("managing %v, the tray window should be ignored", got)
+	}
+	h.wantRect(dialog, layout.Rect{X: 10, Y: 20, W: 300, H: 200})
+	h.wantRect(tray, layout.Rect{X: 0, Y: 0, W: 300, H: 200})
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	// centered in its tile
+	h.wantRect(b, layout.Rect{X: 650, Y: 200, W: 200, H: 100})
+}
+
+func TestConfigureReappliesRules(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a := h.open("a")
+	b := h.b.Open(fake.Window{Title: "b", X: 10, Y: 20, W: 300, H: 200})
+	c := h.open("c")
+	h.b.Focus(c)
+	h.settle()
+	h.exec("toggle-float")
+
+	// b floats, c was floated by hand and no rule says otherwise, a is let go
+	h.m.Configure(Options{MasterFrac: 0.5, Rules: ruleSet(t,
+		"title=a ignore",
+		"title=b float",
+	)})
+	h.settle()
+	if got := h.m.Windows(); len(got) != 2 || h.m.windows[a] != nil {
+		t.Fatalf("managing %v, a should have been let go", got)
+	}
+	if !h.m.Floating(b) || !h.m.Floating(c) {
+		t.Fatal("b and c should both float")
+	}
+	h.wantRect(b, layout.Rect{X: 10, Y: 20, W: 300, H: 200})
+
+	// a tile rule takes the floating back, a size rule fixes the size
+	h.m.Configure(Options{MasterFrac: 0.5, Rules: ruleSet(t,
+		"title=b tile size 200x100",
+		"title=c tile",
+	)})
+	h.settle()
+	if h.m.Floating(b) || h.m.Floating(c) {
+		t.Fatal("b and c still float")
+	}
+	h.wantRect(b, layout.Rect{X: 150, Y: 200, W: 200, H: 100})
+	h.wantRect(c, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+
+	// and dropping it frees it again
+	h.m.Configure(Options{MasterFrac: 0.5})
+	h.settle()
+	h.wantRect(b, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+}
+
+func TestConfigureShowsIgnoredHiddenWindow(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	h.open("a")
+	b := h.open("b")
+	h.exec("send-to-workspace 2")
+	if w, _ := h.b.Window(b); !w.Hidden {
+		t.Fatal("b isn't hidden on workspace 2")
+	}
+
+	// nothing would ever show it again once glo lets go of it
+	h.m.Configure(Options{MasterFrac: 0.5, Rules: ruleSet(t, "title=b ignore")})
+	h.settle()
+	if w, _ := h.b.Window(b); w.Hidden {
+		t.Fatal("b was let go while hidden")
+	}
+}
@@ -0,0 +1,117 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/workspace"
+)
+
+// SetColumnWidth makes the focused window's column frac of the work area
+// wide in the scrolling layout
+func (m *WindowManager) SetColumnWidth(frac float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ws := m.scroller()
+	if s != nil && s.SetColumnWidth(ws.Items(m.inLayout, m.weights), m.focused, frac) {
+		m.scrollTo(m.focused)
+		m.triggerTile()
+	}
+}
+
+// CycleColumnWidth steps the focused window's column through
+// layout.ColumnWidths, from the nearest preset past its current width
+func (m *WindowManager) CycleColumnWidth(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ws := m.scroller()
+	if s == nil {
+		return
+	}
+	items := ws.Items(m.inLayout, m.weights)
+	cur := s.ColumnWidth(items, m.focused, m.params())
+	if cur == 0 {
+		return
+	}
+
+	presets := layout.ColumnWidths
+	next := presets[0]
+	if delta < 0 {
+		next = presets[len(presets)-1]
+		for i := len(presets) - 1; i >= 0; i-- {
+			if presets[i] < cur-0.01 {
+				next = presets[i]
+				break
+			}
+		}
+	} else {
+		for _, w := range presets {
+			if w > cur+0.01 {
+				next = w
+				break
+			}
+		}
+	}
+	if s.SetColumnWidth(items, m.focused, next) {
+		m.scrollTo(m.focused)
+		m.triggerTile()
+	}
+}
+
+// Consume moves the focused window into the column on its left in the
+// scrolling layout
+func (m *WindowManager) Consume() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ws := m.scroller()
+	if s != nil && s.Consume(ws.Items(m.inLayout, m.weights), m.focused) {
+		m.scrollTo(m.focused)
+		m.triggerTile()
+	}
+}
+
+// Expel takes the focused window out of its column into a new one on the
+// right in the scrolling layout
+func (m *WindowManager) Expel() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ws := m.scroller()
+	if s == nil {
+		return
+	}
+	after, ok := s.Expel(ws.Items(m.inLayout, m.weights), m.focused)
+	if !ok {
+		return
+	}
+	if after != 0 {
+		ws.MoveAfter(m.focused, after)
+	}
+	m.scrollTo(m.focused)
+	m.triggerTile()
+}
+
+// scrollTo brings hwnd into view if its workspace scrolls and reports
+// whether that moved anything, m.mu must be held
+func (m *WindowManager) scrollTo(hwnd uintptr) bool {
+	mon, ws := m.find(hwnd)
+	if ws == nil || !m.inLayout(hwnd) {
+		return false
+	}
+	return ws.ScrollTo(hwnd, mon.area, m.params(), m.inLayout, m.weights)
+}
+
+// scroller is the showing workspace of the focused window if its layout is
+// a layout.Scroller, m.mu must be held
+func (m *WindowManager) scroller() (layout.Scroller, *workspace.Workspace) {
+	_, ws := m.showing(m.focused)
+	if ws == nil || !m.inLayout(m.focused) {
+		return nil, nil
+	}
+	s, ok := ws.Layout.(layout.Scroller)
+	if !ok {
+		return nil, nil
+	}
+	return s, ws
+}
@@ -0,0 +1,67 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/platform"
+	"testing"
+)
+
+func TestScrolledColumnsStayOffOtherMonitors(t *testing.T) {
+	h := newHarness(t, Options{Layout: "scrolling"},
+		platform.Monitor{Handle: 1, Primary: true, W: 1000, H: 500, WorkW: 1000, WorkH: 500},
+		platform.Monitor{Handle: 2, X: 1000, W: 1000, H: 500, WorkX: 1000, WorkW: 1000, WorkH: 500},
+	)
+	h.m.Toggle()
+
+	var hwnds []uintptr
+	for _, title := range []string{"a", "b", "c", "d"} {
+		hwnds = append(hwnds, h.open(title))
+	}
+	other := h.open("other")
+	h.exec("move-to-monitor next")
+	h.b.Focus(hwnds[0])
+	h.settle()
+
+	second := layout.Rect{X: 1000, W: 1000, H: 500}
+	h.wantRect(other, layout.Rect{X: 1000, W: 500, H: 500})
+	h.wantRect(hwnds[0], layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(hwnds[1], layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+	for _, hwnd := range hwnds[2:] {
+		r := h.rect(hwnd)
+		if overlaps(r, second) || overlaps(r, layout.Rect{W: 1000, H: 500}) {
+			t.Errorf("off screen column %#x at %v is on a monitor", hwnd, r)
+		}
+	}
+
+	// scrolling to the last column brings it back and parks the first ones
+	// left of everything, in order
+	h.b.Focus(hwnds[3])
+	h.settle()
+	h.wantRect(hwnds[2], layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(hwnds[3], layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+	a, b := h.rect(hwnds[0]), h.rect(hwnds[1])
+	if a.X+a.W > 0 || b.X+b.W > 0 || a.X >= b.X {
+		t.Errorf("parked columns at %v and %v", a, b)
+	}
+}
+
+func TestQueriesDontScroll(t *testing.T) {
+	h := newHarness(t, Options{Layout: "scrolling"})
+	h.m.Toggle()
+	a := h.open("a")
+	h.open("b")
+	c := h.open("c")
+	h.wantRect(c, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+
+	s := h.m.current().active().Layout.(*layout.Scrolling)
+	offset := s.Offset()
+	h.m.mu.Lock()
+	h.m.focused = a
+	h.m.geometry(h.m.current())
+	h.m.mu.Unlock()
+	h.m.QueryWindows()
+	h.m.QueryWorkspaces()
+	if s.Offset() != offset {
+		t.Fatalf("reading positions scrolled from %d to %d", offset, s.Offset())
+	}
+}
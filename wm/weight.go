@@ -0,0 +1,56 @@
+package wm
+
+import "glo/layout"
+
+const (
+	// each grow-window multiplies the focused window's weight by weightStep
+	weightStep = 1.25
+	minWeight  = 0.2
+	maxWeight  = 5
+)
+
+// GrowWindow gives the focused window more of the space it splits with its
+// neighbours, in whatever layout it's in
+func (m *WindowManager) GrowWindow() {
+	m.scaleWeight(weightStep)
+}
+
+func (m *WindowManager) ShrinkWindow() {
+	m.scaleWeight(1 / weightStep)
+}
+
+func (m *WindowManager) scaleWeight(f float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ws := m.showing(m.focused); ws == nil || !m.inLayout(m.focused) {
+		return
+	}
+	w, ok := m.weights[m.focused]
+	if !ok {
+		w = 1
+	}
+	w = min(max(w*f, minWeight), maxWeight)
+	if w == 1 {
+		delete(m.weights, m.focused)
+	} else {
+		m.weights[m.focused] = w
+	}
+	m.triggerTile()
+}
+
+// Equalize evens out every window on the current workspace, and any sizes
+// its layout keeps of its own
+func (m *WindowManager) Equalize() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws := m.current().active()
+	for _, hwnd := range ws.Windows() {
+		delete(m.weights, hwnd)
+	}
+	if e, ok := ws.Layout.(layout.Equalizer); ok {
+		e.Equalize()
+	}
+	m.triggerTile()
+}
@@ -0,0 +1,32 @@
+package wm
+
+import "testing"
+
+func TestGrowWindowEveryLayout(t *testing.T) {
+	for _, name := range []string{"master-stack", "dwindle", "centered-master", "grid", "columns", "rows", "tree"} {
+		t.Run(name, func(t *testing.T) {
+			h := newHarness(t, Options{Layout: name})
+			h.m.Toggle()
+			h.open("a")
+			h.open("b")
+			b := h.open("c")
+			if name == "dwindle" {
+				// two windows, so the master split is the only one there is
+				h.b.Destroy(b)
+				h.settle()
+				b = h.m.Windows()[1]
+				h.b.Focus(b)
+				h.settle()
+			}
+			before := h.rect(b)
+
+			h.exec("grow-window")
+			after := h.rect(b)
+			if after.W*after.H <= before.W*before.H {
+				t.Fatalf("grow-window took %v to %v", before, after)
+			}
+			h.exec("equalize")
+			h.wantRect(b, before)
+		})
+	}
+}
@@ -0,0 +1,747 @@
+package wm
+
+import (
+	"fmt"
+	"glo/layout"
+	"glo/platform"
+	"glo/rules"
+	"glo/window"
+	"glo/workspace"
+	"sync"
+	"time"
+)
+
+const (
+	masterStep = 0.05
+
+	// retiles closer together than debounceWindow are folded into one that
+	// runs debounceDelay later
+	debounceWindow = 40 * time.Millisecond
+	debounceDelay  = 50 * time.Millisecond
+)
+
+type Options struct {
+	// Gaps are the gaps every workspace starts with
+	Gaps layout.Gaps
+	// SmartGaps drops the gaps on a workspace with only one tiled window
+	SmartGaps  bool
+	MasterFrac float64
+	// NMaster is how many windows share the master area
+	NMaster int
+	// Orientation is the side the master goes on
+	Orientation layout.Orientation
+	// Layout is the layout.New name every workspace starts with
+	Layout string
+	// WideLayout replaces Layout on monitors whose work area is more than
+	// WideAspect times as wide as it is high, if it's set
+	WideLayout string
+	WideAspect float64
+	// SideWeights and Alternate shape the side columns of the centered
+	// layouts, see layout.Params
+	SideWeights [2]float64
+	Alternate   bool
+	// EvenRows spreads the grid layout's windows evenly over its rows
+	EvenRows bool
+	// ColumnWidth is how wide new columns of the scrolling layout are
+	ColumnWidth float64
+	AdoptOrder  AdoptOrder
+	// Rules are checked in order when a window shows up, first match wins
+	Rules rules.Set
+}
+
+// WindowManager owns the list of managed windows and the tiling state, every
+// change to either goes through its methods
+type WindowManager struct {
+	b     platform.Backend
+	clock Clock
+
+	mu       sync.Mutex
+	monitors []*monitor
+	// every managed window, whichever workspace it's on and even while minimized
+	windows   map[uintptr]*window.Window
+	minimized map[uintptr]bool
+	// windows glo hid because their workspace isn't showing
+	hidden map[uintptr]bool
+	// windows a rule told glo to leave alone
+	ignored map[uintptr]bool
+	// managed windows left out of the layout, and where each one was last
+	// floating so re-floating puts it back there
+	floating   map[uintptr]bool
+	floatRects map[uintptr]layout.Rect
+	// windows a rule gave a fixed size
+	fixed map[uintptr]size
+	// tiled windows blown up to the whole work area, gaps and all
+	fullscreen map[uintptr]bool
+	// how much room windows take next to the others splitting the same
+	// space, for as long as they're open. missing means 1
+	weights map[uintptr]float64
+	focused uintptr
+	tiling  bool
+
+	gaps      layout.Gaps
+	smartGaps bool
+	// starting master fraction, master count, orientation and layout of
+	// every workspace
+	masterFrac  float64
+	nmaster     int
+	orientation layout.Orientation
+	layoutName  string
+	wideLayout  string
+	wideAspect  float64
+
+	sideWeights [2]float64
+	alternate   bool
+	evenRows    bool
+	columnWidth float64
+	adoptOrder  AdoptOrder
+	rules       rules.Set
+
+	// hotkey id -> command
+	bindings map[int]string
+	done     chan struct{}
+	quitOnce sync.Once
+
+	subscribers map[chan Event]bool
+	// the workspace subscribers last heard was in front
+	announced struct {
+		monitor   uintptr
+		workspace int
+	}
+
+	lastTile time.Time
+	pending  bool
+}
+
+func New(b platform.Backend, clock Clock, opts Options) *WindowManager {
+	m := &WindowManager{
+		b:           b,
+		clock:       clock,
+		windows:     make(map[uintptr]*window.Window),
+		minimized:   make(map[uintptr]bool),
+		hidden:      make(map[uintptr]bool),
+		ignored:     make(map[uintptr]bool),
+		floating:    make(map[uintptr]bool),
+		floatRects:  make(map[uintptr]layout.Rect),
+		fixed:       make(map[uintptr]size),
+		fullscreen:  make(map[uintptr]bool),
+		weights:     make(map[uintptr]float64),
+		gaps:        opts.Gaps,
+		smartGaps:   opts.SmartGaps,
+		masterFrac:  opts.MasterFrac,
+		nmaster:     max(opts.NMaster, 1),
+		orientation: opts.Orientation,
+		layoutName:  opts.Layout,
+		wideLayout:  opts.WideLayout,
+		wideAspect:  opts.WideAspect,
+		sideWeights: opts.SideWeights,
+		alternate:   opts.Alternate,
+		evenRows:    opts.EvenRows,
+		columnWidth: opts.ColumnWidth,
+		adoptOrder:  opts.AdoptOrder,
+		rules:       opts.Rules,
+		bindings:    make(map[int]string),
+		done:        make(chan struct{}),
+
+		subscribers: make(map[chan Event]bool),
+	}
+
+	m.refreshMonitors()
+	if len(m.monitors) == 0 {
+		// no display info at all, tile onto nothing rather than crash
+		m.monitors = []*monitor{{primary: true, workspaces: m.newWorkspaces(layout.Rect{})}}
+	}
+	cur := m.current()
+	m.announced.monitor, m.announced.workspace = cur.handle, cur.active().ID
+	return m
+}
+
+// Configure applies new options to a running manager. workspaces keep the
+// master fraction and layout they've been adjusted to unless the configured
+// default itself changed
+func (m *WindowManager) Configure(opts Options) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.smartGaps = opts.SmartGaps
+	m.sideWeights = opts.SideWeights
+	m.alternate = opts.Alternate
+	m.evenRows = opts.EvenRows
+	m.columnWidth = opts.ColumnWidth
+	m.adoptOrder = opts.AdoptOrder
+	m.rules = opts.Rules
+	// the new rules get a fresh look at everything they skipped before, and
+	// at everything already managed
+	m.ignored = make(map[uintptr]bool)
+	m.reapplyRules()
+
+	if opts.MasterFrac != m.masterFrac {
+		m.masterFrac = opts.MasterFrac
+		for _, ws := range m.allWorkspaces() {
+			ws.MasterFrac = m.masterFrac
+		}
+		m.emit(Event{Type: EventMasterResized, MasterFrac: m.masterFrac})
+	}
+	if nmaster := max(opts.NMaster, 1); nmaster != m.nmaster {
+		m.nmaster = nmaster
+		for _, ws := range m.allWorkspaces() {
+			ws.NMaster = m.nmaster
+		}
+		m.emit(Event{Type: EventNMasterChanged, NMaster: m.nmaster})
+	}
+	if opts.Orientation != m.orientation {
+		m.orientation = opts.Orientation
+		for _, ws := range m.allWorkspaces() {
+			ws.Orientation = m.orientation
+		}
+		m.emit(Event{Type: EventOrientationChanged, Orientation: m.orientation.String()})
+	}
+	if opts.Gaps != m.gaps {
+		m.gaps = opts.Gaps
+		for _, ws := range m.allWorkspaces() {
+			ws.Gaps = m.gaps
+		}
+		m.emit(Event{Type: EventGapsChanged, Gaps: &m.gaps})
+	}
+	if opts.Layout != m.layoutName || opts.WideLayout != m.wideLayout || opts.WideAspect != m.wideAspect {
+		m.layoutName = opts.Layout
+		m.wideLayout, m.wideAspect = opts.WideLayout, opts.WideAspect
+		for _, mon := range m.monitors {
+			for _, ws := range mon.workspaces.All() {
+				ws.Layout = m.newLayout(mon.area)
+			}
+		}
+		m.emit(Event{Type: EventLayoutChanged, Layout: m.current().active().Layout.Name()})
+	}
+
+	if m.tiling {
+		m.tile()
+	}
+}
+
+// Run handles backend events and hotkeys until stop is closed or the backend
+// shuts down
+func (m *WindowManager) Run(stop <-chan struct{}) {
+	events := m.b.Events()
+	hotkeys := m.b.Hotkeys()
+	for {
+		select {
+		case <-stop:
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			m.HandleEvent(ev)
+		case id, ok := <-hotkeys:
+			if !ok {
+				return
+			}
+			m.handleHotkey(id)
+		}
+	}
+}
+
+func (m *WindowManager) HandleEvent(ev platform.Event) {
+	switch ev.Kind {
+	case platform.EventCreate, platform.EventShow:
+		m.Manage(ev.Hwnd)
+	case platform.EventForeground:
+		m.Manage(ev.Hwnd)
+		m.focus(ev.Hwnd)
+	case platform.EventHide:
+		// hiding inactive workspaces fires these too, and by the time the
+		// event arrives the workspace may already be showing again
+		if !m.hiddenByUs(ev.Hwnd) && !m.b.IsAppWindow(ev.Hwnd) {
+			m.Unmanage(ev.Hwnd)
+		}
+	case platform.EventDestroy:
+		m.Unmanage(ev.Hwnd)
+	case platform.EventMinimizeStart:
+		m.minimize(ev.Hwnd)
+	case platform.EventMinimizeEnd:
+		m.restore(ev.Hwnd)
+	case platform.EventMoveSizeEnd:
+		m.moved(ev.Hwnd)
+	}
+}
+
+// Manage adds hwnd to the end of the active workspace on the monitor it is
+// on, it reports whether hwnd is now managed by this call
+func (m *WindowManager) Manage(hwnd uintptr) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.windows[hwnd] != nil || m.ignored[hwnd] || !m.b.IsAppWindow(hwnd) {
+		return false
+	}
+
+	rule, _ := m.ruleFor(hwnd)
+	if rule.Ignore {
+		m.ignored[hwnd] = true
+		return false
+	}
+
+	w, err := window.New(m.b, hwnd)
+	if err != nil {
+		// it closed since IsAppWindow looked at it
+		fmt.Printf("[wm] not managing %#x: %v\n", hwnd, err)
+		return false
+	}
+	m.windows[hwnd] = w
+	defer m.emit(Event{Type: EventWindowManaged, Hwnd: hwnd})
+	if w.IsMinimized() {
+		m.minimized[hwnd] = true
+	}
+
+	m.place(hwnd, rule)
+	if m.tiling {
+		m.tile()
+	}
+	return true
+}
+
+// Unmanage forgets hwnd and closes the gap it leaves, the window itself is
+// left where it is
+func (m *WindowManager) Unmanage(hwnd uintptr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.ignored, hwnd)
+	if m.unmanage(hwnd) && m.tiling {
+		m.tile()
+	}
+}
+
+// unmanage is Unmanage without the retile, it reports whether hwnd was
+// managed. m.mu must be held
+func (m *WindowManager) unmanage(hwnd uintptr) bool {
+	if m.windows[hwnd] == nil {
+		return false
+	}
+	delete(m.windows, hwnd)
+	delete(m.minimized, hwnd)
+	delete(m.hidden, hwnd)
+	delete(m.floating, hwnd)
+	delete(m.floatRects, hwnd)
+	delete(m.fixed, hwnd)
+	delete(m.fullscreen, hwnd)
+	delete(m.weights, hwnd)
+	defer m.emit(Event{Type: EventWindowUnmanaged, Hwnd: hwnd})
+
+	if _, ws := m.find(hwnd); ws != nil {
+		ws.Remove(hwnd)
+	}
+	return true
+}
+
+// place puts a new window on its monitor's active workspace, or wherever its
+// rule asks for, and applies the rest of the rule. m.mu must be held
+func (m *WindowManager) place(hwnd uintptr, rule rules.Rule) {
+	if rule.Float {
+		m.floating[hwnd] = true
+	}
+	if rule.Width > 0 {
+		m.fixed[hwnd] = size{rule.Width, rule.Height}
+	}
+
+	mon := m.monitorOf(hwnd)
+	if rule.Monitor > 0 && rule.Monitor <= len(m.monitors) {
+		to := m.monitors[rule.Monitor-1]
+		// tiling will put a tiled window there by itself
+		if to != mon && (m.floating[hwnd] || !m.tiling) {
+			m.moveOnto(m.windows[hwnd], mon, to)
+		}
+		mon = to
+	}
+	if sz, ok := m.fixed[hwnd]; ok && m.floating[hwnd] && !m.minimized[hwnd] {
+		r := centered(mon.area, sz)
+		m.windows[hwnd].SetRect(r.X, r.Y, r.W, r.H)
+	}
+
+	ws := mon.active()
+	if target := mon.workspaces.Get(rule.Workspace); target != nil {
+		ws = target
+	}
+	ws.Add(hwnd)
+	if rule.Master {
+		ws.Promote(hwnd)
+	}
+	if ws != mon.active() {
+		m.syncVisibility()
+	}
+}
+
+func (m *WindowManager) hiddenByUs(hwnd uintptr) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.hidden[hwnd]
+}
+
+func (m *WindowManager) focus(hwnd uintptr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := hwnd != m.focused
+	m.focused = hwnd
+	if _, ws := m.find(hwnd); ws != nil {
+		ws.Focus(hwnd)
+	}
+	if changed {
+		m.emit(Event{Type: EventFocusChanged, Hwnd: hwnd})
+		// the strip has to scroll to the newly focused column
+		if m.scrollTo(hwnd) {
+			m.triggerTile()
+		}
+	}
+	m.announceWorkspace()
+}
+
+// minimize keeps hwnd in its slot, and floating if it was, but out of the
+// layout until it comes back
+func (m *WindowManager) minimize(hwnd uintptr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.windows[hwnd] == nil {
+		return
+	}
+	m.minimized[hwnd] = true
+	if m.tiling {
+		m.tile()
+	}
+}
+
+func (m *WindowManager) restore(hwnd uintptr) {
+	m.mu.Lock()
+	if m.windows[hwnd] == nil {
+		m.mu.Unlock()
+		// minimized before glo saw it, treat it like a new window
+		m.Manage(hwnd)
+		return
+	}
+	defer m.mu.Unlock()
+
+	delete(m.minimized, hwnd)
+	mon, ws := m.find(hwnd)
+	switch {
+	case ws == nil:
+		m.monitorOf(hwnd).active().Add(hwnd)
+	case ws != mon.active():
+		// restored from the taskbar while its workspace is hidden, it
+		// joins the one showing
+		ws.Remove(hwnd)
+		mon.active().Add(hwnd)
+	}
+	if m.tiling {
+		m.tile()
+	}
+}
+
+// moved handles the user dropping a window, if it landed on another monitor
+// it joins that monitor's active workspace, either way tiling snaps it back
+// into place
+func (m *WindowManager) moved(hwnd uintptr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	from, ws := m.find(hwnd)
+	if from == nil {
+		return
+	}
+	if to := m.monitorOf(hwnd); to != from {
+		ws.Remove(hwnd)
+		to.active().Add(hwnd)
+		to.active().Focus(hwnd)
+	}
+	if m.tiling {
+		m.tile()
+	}
+}
+
+func (m *WindowManager) Toggle() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tiling = !m.tiling
+	tiling := m.tiling
+	defer m.emit(Event{Type: EventTilingToggled, Tiling: &tiling})
+	if m.tiling {
+		m.refreshMonitors()
+		m.adopt()
+		m.tile()
+		return
+	}
+
+	for _, mon := range m.monitors {
+		for _, w := range m.visible(mon.active()) {
+			if m.floating[w.Hwnd()] {
+				continue
+			}
+			w.Restore()
+			w.SetRect(w.Meta.Ox, w.Meta.Oy, w.Meta.Ow, w.Meta.Oh)
+		}
+	}
+}
+
+func (m *WindowManager) GrowMaster() {
+	m.resizeMaster(masterStep)
+}
+
+func (m *WindowManager) ShrinkMaster() {
+	m.resizeMaster(-masterStep)
+}
+
+func (m *WindowManager) resizeMaster(delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.adjustMaster(m.current().active(), delta)
+}
+
+// GrowSplit grows the focused window's own split in layouts that have one
+// per window (dwindle), anywhere else it grows the master area
+func (m *WindowManager) GrowSplit() {
+	m.resizeSplit(masterStep)
+}
+
+func (m *WindowManager) ShrinkSplit() {
+	m.resizeSplit(-masterStep)
+}
+
+func (m *WindowManager) resizeSplit(delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ws := m.showing(m.focused)
+	if ws == nil {
+		ws = m.current().active()
+	}
+	master, ok := ws.AdjustSplit(m.focused, delta, m.inLayout, m.weights)
+	if ok {
+		m.triggerTile()
+		return
+	}
+	m.adjustMaster(ws, master)
+}
+
+// adjustMaster nudges ws's master fraction, m.mu must be held
+func (m *WindowManager) adjustMaster(ws *workspace.Workspace, delta float64) {
+	old := ws.MasterFrac
+	ws.MasterFrac += delta
+	if ws.MasterFrac > 0.9 {
+		ws.MasterFrac = 0.9
+	} else if ws.MasterFrac < 0.1 {
+		ws.MasterFrac = 0.1
+	}
+	if ws.MasterFrac != old {
+		m.emit(Event{Type: EventMasterResized, MasterFrac: ws.MasterFrac})
+	}
+	m.triggerTile()
+}
+
+// IncMaster lets one more window into the master area of the current
+// workspace, up to every tiled window
+func (m *WindowManager) IncMaster() {
+	m.adjustNMaster(1)
+}
+
+func (m *WindowManager) DecMaster() {
+	m.adjustNMaster(-1)
+}
+
+func (m *WindowManager) adjustNMaster(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws := m.current().active()
+	n := min(max(ws.NMaster+delta, 1), max(len(m.tiled(ws)), 1))
+	if n == ws.NMaster {
+		return
+	}
+	ws.NMaster = n
+	m.emit(Event{Type: EventNMasterChanged, NMaster: n})
+	m.triggerTile()
+}
+
+// Rotate moves the master of the current workspace to the bottom of its
+// stack and promotes the next window
+func (m *WindowManager) Rotate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws := m.current().active()
+	if len(m.tiled(ws)) < 2 {
+		return
+	}
+
+	ws.Rotate()
+	m.triggerTile()
+}
+
+// Shutdown puts every managed window back where it was before glo touched
+// it, including the ones on hidden workspaces
+func (m *WindowManager) Shutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tiling = false
+	for hwnd, w := range m.windows {
+		if m.hidden[hwnd] {
+			delete(m.hidden, hwnd)
+			m.b.ShowWindow(hwnd, platform.SW_SHOWNOACTIVATE)
+		}
+		if m.minimized[hwnd] {
+			continue
+		}
+		w.Restore()
+		w.SetRect(w.Meta.Ox, w.Meta.Oy, w.Meta.Ow, w.Meta.Oh)
+	}
+}
+
+func (m *WindowManager) Tiling() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.tiling
+}
+
+func (m *WindowManager) MasterFrac() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.current().active().MasterFrac
+}
+
+// Windows returns the tiled windows in layout order, monitor by monitor and
+// workspace by workspace
+func (m *WindowManager) Windows() []uintptr {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []uintptr
+	for _, mon := range m.monitors {
+		for _, ws := range mon.workspaces.All() {
+			out = append(out, ws.Windows()...)
+		}
+	}
+	return out
+}
+
+// triggerTile retiles now, or shortly if a retile just happened, so holding
+// down a resize hotkey doesn't flood every window with moves
+func (m *WindowManager) triggerTile() {
+	if !m.tiling {
+		return
+	}
+
+	if m.clock.Now().Sub(m.lastTile) < debounceWindow {
+		if !m.pending {
+			m.pending = true
+			m.clock.AfterFunc(debounceDelay, func() {
+				m.mu.Lock()
+				defer m.mu.Unlock()
+
+				if m.pending {
+					m.pending = false
+					if m.tiling {
+						m.tile()
+					}
+				}
+			})
+		}
+		return
+	}
+
+	m.tile()
+}
+
+// tile lays out the active workspace of every monitor. m.mu must be held
+func (m *WindowManager) tile() {
+	m.lastTile = m.clock.Now()
+
+	for _, mon := range m.monitors {
+		layout.Apply(m.plan(mon), m.visible(mon.active()))
+	}
+}
+
+// plan is where tiling puts the windows of mon's active workspace
+func (m *WindowManager) plan(mon *monitor) layout.Plan {
+	plan := mon.active().Plan(mon.area, m.params(), m.inLayout, m.weights)
+	m.fixSizes(plan)
+	for i := range plan {
+		if m.fullscreen[plan[i].ID] {
+			plan[i].Rect = mon.area
+		}
+	}
+	if _, ok := mon.active().Layout.(layout.Scroller); ok {
+		m.park(plan, mon)
+	}
+	return plan
+}
+
+// park moves whatever doesn't fit in mon's work area off to the side of
+// every monitor, so columns scrolled out of view don't cover the tiles of
+// the monitor next door. they keep their order so directions still work
+func (m *WindowManager) park(plan layout.Plan, mon *monitor) {
+	left, right := mon.bounds.X, mon.bounds.X+mon.bounds.W
+	for _, other := range m.monitors {
+		left = min(left, other.bounds.X)
+		right = max(right, other.bounds.X+other.bounds.W)
+	}
+
+	// how far the windows hanging over each edge reach into the area
+	var inLeft, inRight int
+	for _, p := range plan {
+		if p.Rect.X < mon.area.X {
+			inLeft = max(inLeft, p.Rect.X+p.Rect.W-mon.area.X)
+		} else if p.Rect.X+p.Rect.W > mon.area.X+mon.area.W {
+			inRight = max(inRight, mon.area.X+mon.area.W-p.Rect.X)
+		}
+	}
+	for i, p := range plan {
+		if p.Rect.X < mon.area.X {
+			plan[i].Rect.X += left - mon.area.X - inLeft
+		} else if p.Rect.X+p.Rect.W > mon.area.X+mon.area.W {
+			plan[i].Rect.X += right - (mon.area.X + mon.area.W) + inRight
+		}
+	}
+}
+
+// params are the layout settings every workspace shares
+func (m *WindowManager) params() layout.Params {
+	return layout.Params{
+		SmartGaps:   m.smartGaps,
+		SideWeights: m.sideWeights,
+		Alternate:   m.alternate,
+		EvenRows:    m.evenRows,
+		ColumnWidth: m.columnWidth,
+	}
+}
+
+// inLayout is whether hwnd gets a place in the layout
+func (m *WindowManager) inLayout(hwnd uintptr) bool {
+	return !m.floating[hwnd] && !m.windows[hwnd].IsMinimized()
+}
+
+// tiled is visible without the floating windows
+func (m *WindowManager) tiled(ws *workspace.Workspace) []*window.Window {
+	var out []*window.Window
+	for _, w := range m.visible(ws) {
+		if !m.floating[w.Hwnd()] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// visible returns the windows of ws that aren't minimized, in layout order
+func (m *WindowManager) visible(ws *workspace.Workspace) []*window.Window {
+	var out []*window.Window
+	for _, hwnd := range ws.Windows() {
+		if w := m.windows[hwnd]; w != nil && !w.IsMinimized() {
+			out = append(out, w)
+		}
+	}
+	return out
+}
@@ -0,0 +1,248 @@
+package wm
+
+import (
+	"fmt"
+	"glo/layout"
+	"glo/platform"
+	"glo/platform/fake"
+	"testing"
+	"time"
+)
+
+// harness drives a manager on the fake backend and clock, every step hands
+// the manager whatever events the backend queued and lets debounced retiles
+// run
+type harness struct {
+	t     *testing.T
+	b     *fake.Backend
+	clock *fake.Clock
+	m     *WindowManager
+}
+
+func newHarness(t *testing.T, opts Options, monitors ...platform.Monitor) *harness {
+	t.Helper()
+	b := fake.New(1000, 500)
+	if len(monitors) > 0 {
+		b.SetMonitors(monitors...)
+	}
+	if opts.MasterFrac == 0 {
+		opts.MasterFrac = 0.5
+	}
+	h := &harness{t: t, b: b, clock: fake.NewClock(time.Unix(0, 0)), m: nil}
+	h.m = New(b, h.clock, opts)
+	return h
+}
+
+// settle feeds queued events to the manager and runs due timers until
+// nothing is left to do
+func (h *harness) settle() {
+	h.t.Helper()
+	for {
+		select {
+		case ev := <-h.b.Events():
+			h.m.HandleEvent(ev)
+		default:
+			h.clock.Advance(time.Second)
+			select {
+			case ev := <-h.b.Events():
+				h.m.HandleEvent(ev)
+				continue
+			default:
+				return
+			}
+		}
+	}
+}
+
+func (h *harness) open(title string) uintptr {
+	h.t.Helper()
+	hwnd := h.b.Open(fake.Window{Title: title, W: 300, H: 200})
+	h.settle()
+	return hwnd
+}
+
+func (h *harness) exec(line string) {
+	h.t.Helper()
+	if err := h.m.Exec(line); err != nil {
+		h.t.Fatalf("%s: %v", line, err)
+	}
+	h.settle()
+}
+
+func (h *harness) rect(hwnd uintptr) layout.Rect {
+	h.t.Helper()
+	w, ok := h.b.Window(hwnd)
+	if !ok {
+		h.t.Fatalf("no window %#x", hwnd)
+	}
+	return layout.Rect{X: w.X, Y: w.Y, W: w.W, H: w.H}
+}
+
+func (h *harness) wantRect(hwnd uintptr, want layout.Rect) {
+	h.t.Helper()
+	if got := h.rect(hwnd); got != want {
+		h.t.Errorf("window %#x at %v, want %v", hwnd, got, want)
+	}
+}
+
+func overlaps(a, b layout.Rect) bool {
+	return a.X < b.X+b.W && b.X < a.X+a.W && a.Y < b.Y+b.H && b.Y < a.Y+a.H
+}
+
+func TestGrowSplitSecondOfTwo(t *testing.T) {
+	h := newHarness(t, Options{Layout: "dwindle"})
+	h.m.Toggle()
+	h.open("a")
+	b := h.open("b")
+	before := h.rect(b)
+
+	h.exec("grow-split")
+	if after := h.rect(b); after.W <= before.W {
+		t.Fatalf("grow-split on the second window took it from %v to %v", before, after)
+	}
+	h.exec("shrink-split")
+	h.wantRect(b, before)
+}
+
+// closing is a backend where some windows still pass IsAppWindow but are
+// gone by the time their rect is read
+type closing struct {
+	*fake.Backend
+	gone map[uintptr]bool
+}
+
+func (c *closing) GetRect(hwnd uintptr) (int, int, int, int, error) {
+	if c.gone[hwnd] {
+		return 0, 0, 0, 0, fmt.Errorf("no window %#x", hwnd)
+	}
+	return c.Backend.GetRect(hwnd)
+}
+
+func TestManageSkipsClosedWindow(t *testing.T) {
+	b := &closing{fake.New(1000, 500), make(map[uintptr]bool)}
+	m := New(b, fake.NewClock(time.Unix(0, 0)), Options{MasterFrac: 0.5})
+	m.Toggle()
+
+	a := b.Open(fake.Window{Title: "a"})
+	gone := b.Open(fake.Window{Title: "gone"})
+	b.gone[gone] = true
+
+	if !m.Manage(a) {
+		t.Fatal("a wasn't managed")
+	}
+	if m.Manage(gone) {
+		t.Fatal("a window without a rect was managed")
+	}
+	if got := m.Windows(); len(got) != 1 || got[0] != a {
+		t.Fatalf("managing %v, want just %#x", got, a)
+	}
+}
+
+func TestManageUnmanage(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a, b, c := h.open("a"), h.open("b"), h.open("c")
+	h.b.Open(fake.Window{Title: "tool", NotApp: true})
+	h.settle()
+
+	if got := h.m.Windows(); len(got) != 3 || got[0] != a || got[1] != b || got[2] != c {
+		t.Fatalf("managing %v, want %#x %#x %#x", got, a, b, c)
+	}
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 250})
+	h.wantRect(c, layout.Rect{X: 500, Y: 250, W: 500, H: 250})
+
+	h.b.Destroy(b)
+	h.settle()
+	if got := h.m.Windows(); len(got) != 2 {
+		t.Fatalf("managing %v after b closed", got)
+	}
+	h.wantRect(c, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+}
+
+func TestRotate(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a, b, c := h.open("a"), h.open("b"), h.open("c")
+
+	h.exec("rotate")
+	if got := h.m.Windows(); got[0] != b || got[1] != c || got[2] != a {
+		t.Fatalf("rotated to %v", got)
+	}
+	h.wantRect(b, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(a, layout.Rect{X: 500, Y: 250, W: 500, H: 250})
+}
+
+func TestMasterLimits(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a, b, c := h.open("a"), h.open("b"), h.open("c")
+
+	h.exec("inc-master")
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 250})
+	h.wantRect(b, layout.Rect{X: 0, Y: 250, W: 500, H: 250})
+	h.wantRect(c, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+	for i := 0; i < 3; i++ {
+		h.exec("inc-master")
+	}
+	if n := h.m.State().NMaster; n != 3 {
+		t.Errorf("nmaster went to %d with three windows", n)
+	}
+	for i := 0; i < 5; i++ {
+		h.exec("dec-master")
+	}
+	if n := h.m.State().NMaster; n != 1 {
+		t.Errorf("nmaster went down to %d", n)
+	}
+
+	for i := 0; i < 20; i++ {
+		h.exec("grow-master")
+	}
+	if f := h.m.MasterFrac(); f != 0.9 {
+		t.Errorf("master grew to %v", f)
+	}
+	for i := 0; i < 20; i++ {
+		h.exec("shrink-master")
+	}
+	if f := h.m.MasterFrac(); f != 0.1 {
+		t.Errorf("master shrank to %v", f)
+	}
+}
+
+func TestRetileDebounce(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a := h.open("a")
+	h.open("b")
+
+	// the first resize in a while retiles straight away
+	h.m.GrowMaster()
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 550, H: 500})
+
+	// the ones right after it are folded into one retile a little later
+	h.m.GrowMaster()
+	h.m.GrowMaster()
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 550, H: 500})
+	h.clock.Advance(debounceDelay - time.Millisecond)
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 550, H: 500})
+	h.clock.Advance(time.Millisecond)
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 650, H: 500})
+
+	// and once things are quiet again it's immediate
+	h.clock.Advance(debounceWindow)
+	h.m.ShrinkMaster()
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 600, H: 500})
+}
+
+func TestRetileDroppedWhenTilingStops(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a := h.open("a")
+	h.open("b")
+
+	h.m.GrowMaster()
+	h.m.GrowMaster()
+	h.m.Toggle()
+	h.clock.Advance(time.Second)
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 300, H: 200})
+}
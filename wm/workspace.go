@@ -0,0 +1,213 @@
+package wm
+
+import (
+	"glo/layout"
+	"glo/platform"
+	"glo/workspace"
+	"slices"
+)
+
+// WorkspaceCount is how many workspaces each monitor has
+const WorkspaceCount = 9
+
+// newWorkspaces makes the workspaces for a monitor with work area area
+func (m *WindowManager) newWorkspaces(area layout.Rect) *workspace.Set {
+	return workspace.NewSet(WorkspaceCount, func(id int) *workspace.Workspace {
+		return workspace.New(id, m.newLayout(area), m.masterFrac, m.nmaster, m.orientation, m.gaps)
+	})
+}
+
+// newLayout builds the configured default layout for a monitor with work
+// area area, the wide one if it's wide enough, falling back to master-stack
+// if the name is unknown
+func (m *WindowManager) newLayout(area layout.Rect) layout.Layout {
+	name := m.layoutName
+	if m.wideLayout != "" && area.H > 0 && float64(area.W)/float64(area.H) > m.wideAspect {
+		name = m.wideLayout
+	}
+	if name != "" {
+		if l, err := layout.New(name); err == nil {
+			return l
+		}
+	}
+	return layout.MasterStack{}
+}
+
+func (m *WindowManager) allWorkspaces() []*workspace.Workspace {
+	var out []*workspace.Workspace
+	for _, mon := range m.monitors {
+		out = append(out, mon.workspaces.All()...)
+	}
+	return out
+}
+
+// SwitchWorkspace shows workspace id on the current monitor and hides the
+// one that was there
+func (m *WindowManager) SwitchWorkspace(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, to := m.current().workspaces.Switch(id)
+	if to == nil {
+		return
+	}
+
+	m.syncVisibility()
+	if m.tiling {
+		m.tile()
+	}
+	m.announceWorkspace()
+	m.focusWorkspace(to)
+}
+
+// SendToWorkspace moves the focused window to workspace id on its monitor
+func (m *WindowManager) SendToWorkspace(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mon, _ := m.showing(m.focused)
+	if mon == nil {
+		return
+	}
+	from, to := mon.workspaces.Move(m.focused, id)
+	if to == nil {
+		return
+	}
+	to.Focus(m.focused)
+
+	m.syncVisibility()
+	if m.tiling {
+		m.tile()
+	}
+	if from == mon.active() {
+		m.focusWorkspace(from)
+	}
+}
+
+// SetLayout switches the current workspace to the named layout
+func (m *WindowManager) SetLayout(name string) error {
+	l, err := layout.New(name)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.setLayout(m.current().active(), l)
+	return nil
+}
+
+// CycleLayout switches the current workspace delta layouts along
+// layout.Names, wrapping around
+func (m *WindowManager) CycleLayout(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws := m.current().active()
+	names := layout.Names()
+	i := slices.Index(names, ws.Layout.Name())
+	n := len(names)
+	l, _ := layout.New(names[((i+delta)%n+n)%n])
+	m.setLayout(ws, l)
+}
+
+// SetOrientation puts the master of the current workspace on side o
+func (m *WindowManager) SetOrientation(o layout.Orientation) {
+	m.turn(func(layout.Orientation) layout.Orientation { return o })
+}
+
+// CycleOrientation turns the current workspace delta sides clockwise
+func (m *WindowManager) CycleOrientation(delta int) {
+	m.turn(func(o layout.Orientation) layout.Orientation { return o.Cycle(delta) })
+}
+
+// MirrorOrientation moves the master of the current workspace to the
+// opposite side
+func (m *WindowManager) MirrorOrientation() {
+	m.turn(layout.Orientation.Mirror)
+}
+
+func (m *WindowManager) turn(f func(layout.Orientation) layout.Orientation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ws := m.current().active()
+	o := f(ws.Orientation)
+	if o == ws.Orientation {
+		return
+	}
+	ws.Orientation = o
+	m.emit(Event{Type: EventOrientationChanged, Orientation: o.String()})
+	m.triggerTile()
+}
+
+func (m *WindowManager) setLayout(ws *workspace.Workspace, l layout.Layout) {
+	ws.Layout = l
+	if m.tiling {
+		m.tile()
+	}
+	m.emit(Event{Type: EventLayoutChanged, Layout: l.Name()})
+}
+
+// ActiveWorkspace is the id of the workspace showing on the current monitor
+func (m *WindowManager) ActiveWorkspace() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.current().active().ID
+}
+
+// focusWorkspace gives focus back to the window last used on ws, or its
+// master if that one is gone
+func (m *WindowManager) focusWorkspace(ws *workspace.Workspace) {
+	target := ws.Focused()
+	if target == 0 || m.minimized[target] {
+		target = 0
+		for _, hwnd := range ws.Windows() {
+			if !m.minimized[hwnd] {
+				target = hwnd
+				break
+			}
+		}
+	}
+	if target != 0 {
+		m.b.Focus(target)
+	}
+}
+
+// syncVisibility hides windows on inactive workspaces and shows the ones on
+// active workspaces, dropping focus if the focused window is hidden. new
+// ones are shown before old ones are hidden so switching doesn't flash the
+// desktop. minimized windows are left in the taskbar, restoring one brings
+// it to the workspace that's showing
+func (m *WindowManager) syncVisibility() {
+	var hide []uintptr
+	for _, mon := range m.monitors {
+		for _, ws := range mon.workspaces.All() {
+			active := ws == mon.active()
+			for _, hwnd := range ws.Windows() {
+				switch {
+				case m.minimized[hwnd] && !m.hidden[hwnd]:
+					// stays in the taskbar
+				case active && m.hidden[hwnd]:
+					delete(m.hidden, hwnd)
+					m.b.ShowWindow(hwnd, platform.SW_SHOWNOACTIVATE)
+				case !active && !m.hidden[hwnd]:
+					hide = append(hide, hwnd)
+				}
+			}
+		}
+	}
+
+	for _, hwnd := range hide {
+		m.hidden[hwnd] = true
+		m.b.ShowWindow(hwnd, platform.SW_HIDE)
+	}
+	// commands mustn't act on a window nobody can see, focus moves on
+	// once something on the showing workspace is focused
+	if m.hidden[m.focused] {
+		m.focused = 0
+		m.emit(Event{Type: EventFocusChanged})
+	}
+}
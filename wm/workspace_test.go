@@ -0,0 +1,49 @@
+package wm
+
+import (
+	"glo/layout"
+	"slices"
+	"testing"
+)
+
+func TestCommandsSkipHiddenFocus(t *testing.T) {
+	h := newHarness(t, Options{})
+	h.m.Toggle()
+	a, b := h.open("a"), h.open("b")
+
+	h.exec("workspace 2")
+	if f := h.m.State().Focused; f != 0 {
+		t.Fatalf("%#x still focused on an empty workspace", f)
+	}
+	for _, cmd := range []string{
+		"send-to-workspace 3",
+		"toggle-float",
+		"toggle-fullscreen",
+		"grow-window",
+		"promote",
+		"swap next",
+		"move-to-monitor next",
+	} {
+		h.exec(cmd)
+	}
+	if occupied := h.m.State().Monitors[0].Occupied; !slices.Equal(occupied, []int{1}) {
+		t.Fatalf("workspaces %v have windows, want just 1", occupied)
+	}
+
+	// back on workspace 1 nothing was touched
+	h.exec("workspace 1")
+	if f := h.m.State().Focused; f != b {
+		t.Fatalf("focused %#x back on workspace 1, want %#x", f, b)
+	}
+	if h.m.Floating(b) {
+		t.Error("b floated from a hidden workspace")
+	}
+	h.wantRect(a, layout.Rect{X: 0, Y: 0, W: 500, H: 500})
+	h.wantRect(b, layout.Rect{X: 500, Y: 0, W: 500, H: 500})
+
+	// and with it showing again commands work
+	h.exec("send-to-workspace 3")
+	if occupied := h.m.State().Monitors[0].Occupied; !slices.Equal(occupied, []int{1, 3}) {
+		t.Fatalf("workspaces %v have windows, want 1 and 3", occupied)
+	}
+}
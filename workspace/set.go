@@ -0,0 +1,66 @@
+package workspace
+
+// Set is a fixed row of workspaces numbered from 1, one of them is active
+type Set struct {
+	workspaces []*Workspace
+	active     int
+}
+
+// NewSet makes n workspaces with newWorkspace, the first one starts active
+func NewSet(n int, newWorkspace func(id int) *Workspace) *Set {
+	s := &Set{}
+	for id := 1; id <= n; id++ {
+		s.workspaces = append(s.workspaces, newWorkspace(id))
+	}
+	return s
+}
+
+func (s *Set) Active() *Workspace {
+	return s.workspaces[s.active]
+}
+
+// Get returns workspace id, or nil if there is no such workspace
+func (s *Set) Get(id int) *Workspace {
+	if id < 1 || id > len(s.workspaces) {
+		return nil
+	}
+	return s.workspaces[id-1]
+}
+
+func (s *Set) All() []*Workspace {
+	return append([]*Workspace(nil), s.workspaces...)
+}
+
+// Find returns the workspace holding hwnd, or nil
+func (s *Set) Find(hwnd uintptr) *Workspace {
+	for _, ws := range s.workspaces {
+		if ws.Contains(hwnd) {
+			return ws
+		}
+	}
+	return nil
+}
+
+// Switch makes workspace id active and returns the workspaces that were and
+// now are active. both are nil if nothing changed
+func (s *Set) Switch(id int) (from, to *Workspace) {
+	to = s.Get(id)
+	if to == nil || to == s.Active() {
+		return nil, nil
+	}
+	from = s.Active()
+	s.active = id - 1
+	return from, to
+}
+
+// Move takes hwnd off its workspace and puts it at the end of workspace id.
+// both are nil if hwnd isn't in the set or is already there
+func (s *Set) Move(hwnd uintptr, id int) (from, to *Workspace) {
+	from, to = s.Find(hwnd), s.Get(id)
+	if from == nil || to == nil || from == to {
+		return nil, nil
+	}
+	from.Remove(hwnd)
+	to.Add(hwnd)
+	return from, to
+}
@@ -0,0 +1,179 @@
+package workspace
+
+import (
+	"glo/layout"
+)
+
+// Workspace is an ordered group of windows with its own layout settings.
+// windows are only known by id so a workspace can be driven without any
+// real windows behind it
+type Workspace struct {
+	ID         int
+	Layout     layout.Layout
+	MasterFrac float64
+	// how many windows share the master area
+	NMaster int
+	// the side the master goes on
+	Orientation layout.Orientation
+	Gaps        layout.Gaps
+
+	windows []uintptr
+	focused uintptr
+}
+
+func New(id int, l layout.Layout, masterFrac float64, nmaster int, o layout.Orientation, gaps layout.Gaps) *Workspace {
+	return &Workspace{ID: id, Layout: l, MasterFrac: masterFrac, NMaster: nmaster, Orientation: o, Gaps: gaps}
+}
+
+// Windows returns the windows in layout order, the first one is master
+func (ws *Workspace) Windows() []uintptr {
+	return append([]uintptr(nil), ws.windows...)
+}
+
+func (ws *Workspace) Len() int {
+	return len(ws.windows)
+}
+
+func (ws *Workspace) Index(hwnd uintptr) int {
+	for i, h := range ws.windows {
+		if h == hwnd {
+			return i
+		}
+	}
+	return -1
+}
+
+func (ws *Workspace) Contains(hwnd uintptr) bool {
+	return ws.Index(hwnd) >= 0
+}
+
+// Add appends hwnd to the end of the stack
+func (ws *Workspace) Add(hwnd uintptr) {
+	if ws.Contains(hwnd) {
+		return
+	}
+	ws.windows = append(ws.windows, hwnd)
+}
+
+func (ws *Workspace) Remove(hwnd uintptr) bool {
+	i := ws.Index(hwnd)
+	if i < 0 {
+		return false
+	}
+	ws.windows = append(ws.windows[:i], ws.windows[i+1:]...)
+	if ws.focused == hwnd {
+		ws.focused = 0
+	}
+	return true
+}
+
+// Rotate moves the master to the bottom of the stack and promotes the next window
+func (ws *Workspace) Rotate() {
+	if len(ws.windows) < 2 {
+		return
+	}
+	first := ws.windows[0]
+	copy(ws.windows, ws.windows[1:])
+	ws.windows[len(ws.windows)-1] = first
+}
+
+// Promote moves hwnd into the master slot, the rest keep their order
+func (ws *Workspace) Promote(hwnd uintptr) bool {
+	i := ws.Index(hwnd)
+	if i < 0 {
+		return false
+	}
+	copy(ws.windows[1:i+1], ws.windows[:i])
+	ws.windows[0] = hwnd
+	return true
+}
+
+// Swap exchanges the layout slots of a and b
+func (ws *Workspace) Swap(a, b uintptr) bool {
+	i, j := ws.Index(a), ws.Index(b)
+	if i < 0 || j < 0 {
+		return false
+	}
+	ws.windows[i], ws.windows[j] = ws.windows[j], ws.windows[i]
+	return true
+}
+
+// MoveAfter takes hwnd out of its slot and puts it right after after
+func (ws *Workspace) MoveAfter(hwnd, after uintptr) bool {
+	i := ws.Index(hwnd)
+	if i < 0 || hwnd == after || !ws.Contains(after) {
+		return false
+	}
+	ws.windows = append(ws.windows[:i], ws.windows[i+1:]...)
+	i = ws.Index(after) + 1
+	ws.windows = append(ws.windows[:i], append([]uintptr{hwnd}, ws.windows[i:]...)...)
+	return true
+}
+
+// Focused is the last focused window on this workspace, 0 if there isn't one
+func (ws *Workspace) Focused() uintptr {
+	return ws.focused
+}
+
+func (ws *Workspace) Focus(hwnd uintptr) {
+	if ws.Contains(hwnd) {
+		ws.focused = hwnd
+	}
+}
+
+// Plan lays out the workspace's windows in area, include can leave some out
+// and weights are their layout.Item weights. p has the settings shared by
+// every workspace, the workspace fills in its own
+func (ws *Workspace) Plan(area layout.Rect, p layout.Params, include func(hwnd uintptr) bool, weights map[uintptr]float64) layout.Plan {
+	p = ws.params(p)
+	for _, h := range ws.windows {
+		if include != nil && !include(h) {
+			p.Skipped = append(p.Skipped, h)
+		}
+	}
+	return layout.Arrange(ws.Layout, ws.Items(include, weights), area, p)
+}
+
+// ScrollTo scrolls hwnd into view if the layout is a layout.Scroller, the
+// rest are the ones given to Plan
+func (ws *Workspace) ScrollTo(hwnd uintptr, area layout.Rect, p layout.Params, include func(hwnd uintptr) bool, weights map[uintptr]float64) bool {
+	s, ok := ws.Layout.(layout.Scroller)
+	if !ok {
+		return false
+	}
+	p = ws.params(p)
+	items := ws.Items(include, weights)
+	return s.ScrollTo(items, hwnd, p.View(area, len(items)), p)
+}
+
+func (ws *Workspace) params(p layout.Params) layout.Params {
+	p.MasterFrac = ws.MasterFrac
+	p.NMaster = ws.NMaster
+	p.Orientation = ws.Orientation
+	p.Gaps = ws.Gaps
+	p.Focused = ws.focused
+	return p
+}
+
+// AdjustSplit grows hwnd's own split if the layout has per-window splits,
+// include must match the one given to Plan. when it doesn't, master is how
+// much the master fraction should change instead
+func (ws *Workspace) AdjustSplit(hwnd uintptr, delta float64, include func(hwnd uintptr) bool, weights map[uintptr]float64) (master float64, ok bool) {
+	s, ok := ws.Layout.(layout.Splitter)
+	if !ok {
+		return delta, false
+	}
+	return s.AdjustSplit(ws.Items(include, weights), hwnd, delta)
+}
+
+// Items is what the layout is handed for the windows include lets through,
+// weighted by weights
+func (ws *Workspace) Items(include func(hwnd uintptr) bool, weights map[uintptr]float64) []layout.Item {
+	items := make([]layout.Item, 0, len(ws.windows))
+	for _, h := range ws.windows {
+		if include == nil || include(h) {
+			items = append(items, layout.Item{ID: h, Weight: weights[h]})
+		}
+	}
+	return items
+}
@@ -0,0 +1,174 @@
+package workspace
+
+import (
+	"glo/layout"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func filled(ids ...uintptr) *Workspace {
+	ws := New(1, layout.MasterStack{}, 0.5, 1, layout.OrientLeft, layout.Gaps{})
+	for _, id := range ids {
+		ws.Add(id)
+	}
+	return ws
+}
+
+func TestOrder(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		do   func(ws *Workspace) bool
+		want []uintptr
+		ok   bool
+	}{
+		{"add twice", func(ws *Workspace) bool { ws.Add(2); return true }, []uintptr{1, 2, 3, 4}, true},
+		{"remove", func(ws *Workspace) bool { return ws.Remove(2) }, []uintptr{1, 3, 4}, true},
+		{"remove missing", func(ws *Workspace) bool { return ws.Remove(9) }, []uintptr{1, 2, 3, 4}, false},
+		{"rotate", func(ws *Workspace) bool { ws.Rotate(); return true }, []uintptr{2, 3, 4, 1}, true},
+		{"promote", func(ws *Workspace) bool { return ws.Promote(3) }, []uintptr{3, 1, 2, 4}, true},
+		{"promote master", func(ws *Workspace) bool { return ws.Promote(1) }, []uintptr{1, 2, 3, 4}, true},
+		{"promote missing", func(ws *Workspace) bool { return ws.Promote(9) }, []uintptr{1, 2, 3, 4}, false},
+		{"swap", func(ws *Workspace) bool { return ws.Swap(1, 4) }, []uintptr{4, 2, 3, 1}, true},
+		{"swap missing", func(ws *Workspace) bool { return ws.Swap(1, 9) }, []uintptr{1, 2, 3, 4}, false},
+		{"move after later", func(ws *Workspace) bool { return ws.MoveAfter(1, 3) }, []uintptr{2, 3, 1, 4}, true},
+		{"move after earlier", func(ws *Workspace) bool { return ws.MoveAfter(4, 1) }, []uintptr{1, 4, 2, 3}, true},
+		{"move after last", func(ws *Workspace) bool { return ws.MoveAfter(2, 4) }, []uintptr{1, 3, 4, 2}, true},
+		{"move after itself", func(ws *Workspace) bool { return ws.MoveAfter(2, 2) }, []uintptr{1, 2, 3, 4}, false},
+		{"move after missing", func(ws *Workspace) bool { return ws.MoveAfter(2, 9) }, []uintptr{1, 2, 3, 4}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ws := filled(1, 2, 3, 4)
+			ok := tc.do(ws)
+			if got := ws.Windows(); !slices.Equal(got, tc.want) || ok != tc.ok {
+				t.Fatalf("got %v, %v, want %v, %v", got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestRotateAlone(t *testing.T) {
+	ws := filled(1)
+	ws.Rotate()
+	if got := ws.Windows(); !slices.Equal(got, []uintptr{1}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestFocus(t *testing.T) {
+	ws := filled(1, 2)
+	ws.Focus(9)
+	if f := ws.Focused(); f != 0 {
+		t.Fatalf("focused %d, a window it doesn't have", f)
+	}
+	ws.Focus(2)
+	if f := ws.Focused(); f != 2 {
+		t.Fatalf("focused %d, want 2", f)
+	}
+	ws.Remove(1)
+	if f := ws.Focused(); f != 2 {
+		t.Fatalf("removing another window moved focus to %d", f)
+	}
+	ws.Remove(2)
+	if f := ws.Focused(); f != 0 {
+		t.Fatalf("focused %d after it was removed", f)
+	}
+}
+
+func TestWindowsIsACopy(t *testing.T) {
+	ws := filled(1, 2)
+	ws.Windows()[0] = 9
+	if got := ws.Windows(); got[0] != 1 {
+		t.Fatalf("changing Windows changed the workspace to %v", got)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	ws := filled(1, 2, 3)
+	ws.Focus(3)
+	area := layout.Rect{X: 0, Y: 0, W: 1000, H: 600}
+	floating := func(h uintptr) bool { return h != 2 }
+
+	got := ws.Plan(area, layout.Params{}, floating, map[uintptr]float64{3: 2})
+	want := layout.Plan{
+		{ID: 1, Rect: layout.Rect{X: 0, Y: 0, W: 500, H: 600}},
+		{ID: 3, Rect: layout.Rect{X: 500, Y: 0, W: 500, H: 600}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	items := ws.Items(floating, map[uintptr]float64{3: 2})
+	if want := []layout.Item{{ID: 1}, {ID: 3, Weight: 2}}; !reflect.DeepEqual(items, want) {
+		t.Fatalf("items %v, want %v", items, want)
+	}
+}
+
+func TestAdjustSplit(t *testing.T) {
+	ws := filled(1, 2)
+	// master-stack has no splits of its own
+	if master, ok := ws.AdjustSplit(2, 0.05, nil, nil); ok || master != 0.05 {
+		t.Fatalf("master-stack AdjustSplit = %v, %v", master, ok)
+	}
+	ws.Layout = layout.NewDwindle()
+	if master, ok := ws.AdjustSplit(2, 0.05, nil, nil); ok || master != -0.05 {
+		t.Fatalf("dwindle AdjustSplit on the second of two = %v, %v", master, ok)
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := NewSet(3, func(id int) *Workspace {
+		return New(id, layout.MasterStack{}, 0.5, 1, layout.OrientLeft, layout.Gaps{})
+	})
+	if s.Active().ID != 1 {
+		t.Fatalf("workspace %d starts active", s.Active().ID)
+	}
+	if s.Get(0) != nil || s.Get(4) != nil {
+		t.Fatal("Get found a workspace out of range")
+	}
+	s.Get(1).Add(10)
+	s.Get(1).Add(11)
+
+	if from, to := s.Switch(1); from != nil || to != nil {
+		t.Fatal("switching to the active workspace changed something")
+	}
+	if from, to := s.Switch(9); from != nil || to != nil {
+		t.Fatal("switched to a workspace that doesn't exist")
+	}
+	if from, to := s.Switch(2); from.ID != 1 || to.ID != 2 || s.Active().ID != 2 {
+		t.Fatalf("switch to 2 went %v -> %v", from.ID, to.ID)
+	}
+
+	// moves go to the end of the other workspace
+	s.Get(3).Add(12)
+	if from, to := s.Move(10, 3); from.ID != 1 || to.ID != 3 {
+		t.Fatal("move 10 to 3 failed")
+	}
+	if got := s.Get(3).Windows(); !slices.Equal(got, []uintptr{12, 10}) {
+		t.Fatalf("workspace 3 has %v", got)
+	}
+	if s.Find(10).ID != 3 || s.Find(11).ID != 1 || s.Find(99) != nil {
+		t.Fatal("Find is out of date")
+	}
+	for _, tc := range []struct {
+		hwnd uintptr
+		id   int
+	}{{10, 3}, {99, 1}, {11, 7}} {
+		if from, to := s.Move(tc.hwnd, tc.id); from != nil || to != nil {
+			t.Errorf("Move(%d, %d) did something", tc.hwnd, tc.id)
+		}
+	}
+}
+
+func TestSetsAreSeparate(t *testing.T) {
+	newWorkspace := func(id int) *Workspace {
+		return New(id, layout.MasterStack{}, 0.5, 1, layout.OrientLeft, layout.Gaps{})
+	}
+	// every monitor has a set of its own
+	a, b := NewSet(2, newWorkspace), NewSet(2, newWorkspace)
+	a.Get(1).Add(1)
+	a.Switch(2)
+	if b.Find(1) != nil || b.Active().ID != 1 {
+		t.Fatal("sets share state")
+	}
+}